@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	bumpDryRun bool
+	bumpYes    bool
+	bumpTidy   bool
+	bumpProxy  string
+)
+
+var bumpCmd = &cobra.Command{
+	Use:   "bump <module>@<version> [directory]",
+	Short: "Update a dependency version across all dependent repos",
+	Long: `Update the require line for a module in every go.mod that depends on it,
+walking the dependents in dependency order.
+
+For a module owned by a scanned repo, all repos transitively depending on it
+are visited; for other modules, the repos requiring it. Requirements already at
+or above the version are left unchanged.
+
+Each change is shown as a diff and applied after confirmation. Use --tidy to run
+"go mod tidy" in each updated module afterwards, with GOFLAGS=-mod=mod and
+GOWORK=off. It runs offline against the module cache by default (GOPROXY=off);
+use --proxy to set another GOPROXY, such as a local file:// proxy.
+
+Examples:
+  gitscan bump github.com/grokify/mogo@v0.75.0 ~/go/src/github.com/grokify
+  gitscan bump github.com/grokify/mogo@v0.75.0 -n ~/go/src/github.com/grokify  # Dry run
+  gitscan bump golang.org/x/mod@v0.35.0 --tidy -r ~/go/src/github.com/grokify`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runBump,
+}
+
+func init() {
+	bumpCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	bumpCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Include nested go.mod modules in the dependency graph")
+	bumpCmd.Flags().BoolVarP(&bumpDryRun, "dry-run", "n", false, "Show the diffs without writing any files")
+	bumpCmd.Flags().BoolVarP(&bumpYes, "yes", "y", false, "Apply changes without asking for confirmation")
+	bumpCmd.Flags().BoolVar(&bumpTidy, "tidy", false, "Run go mod tidy in each updated module")
+	bumpCmd.Flags().StringVar(&bumpProxy, "proxy", "off", "GOPROXY for go mod tidy")
+	bumpCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(bumpCmd)
+}
+
+func runBump(cmd *cobra.Command, args []string) error {
+	modulePath, version, ok := strings.Cut(args[0], "@")
+	if !ok || !semver.IsValid(version) {
+		return fmt.Errorf("invalid argument %q: expected <module>@<version>, e.g. github.com/grokify/mogo@v0.75.0", args[0])
+	}
+	if err := module.Check(modulePath, version); err != nil {
+		return err
+	}
+	scanDir, err := scanDirArg(args, 1, "gitscan bump <module>@<version> [directory]")
+	if err != nil {
+		return err
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		GitBackend: createGitBackend(useGoGit),
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	// Visit the owner's dependents, or the repos requiring an external module
+	affected := make(map[string]bool)
+	if node, ok := scanner.ModuleNodes(results)[modulePath]; ok {
+		for _, r := range results {
+			if r.Name == node.Repo {
+				for _, d := range scanner.GetTransitiveDependents([]scanner.RepoResult{r}, results) {
+					affected[d.Name] = true
+				}
+				break
+			}
+		}
+		fmt.Printf("\n%s is owned by %s (%d dependents)\n", modulePath, node.Repo, len(affected)-1)
+	} else {
+		for _, r := range results {
+			if r.HasDependency(modulePath) {
+				affected[r.Name] = true
+			}
+		}
+		fmt.Printf("\n%s is required by %d repos\n", modulePath, len(affected))
+	}
+
+	sorted, cycles := scanner.TopologicalSort(results)
+	printCycles(cycles)
+
+	var edits []scanner.GoModEdit
+	for _, r := range sorted {
+		if !affected[r.Name] {
+			continue
+		}
+		repoEdits, err := scanner.SetRequirement(r, modulePath, version)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		edits = append(edits, repoEdits...)
+	}
+
+	applied, err := applyGoModEdits(edits, bumpDryRun, bumpYes)
+	if err != nil || len(applied) == 0 {
+		return err
+	}
+	if !bumpTidy {
+		fmt.Println("Run `go mod tidy` in each to refresh go.sum, or use --tidy.")
+		return nil
+	}
+
+	fmt.Printf("\nRunning go mod tidy (GOPROXY=%s):\n", bumpProxy)
+	failed := 0
+	for i, edit := range applied {
+		name := filepath.Join(edit.Repo, filepath.Dir(edit.GoModPath))
+		if out, err := goModTidy(filepath.Dir(edit.File), bumpProxy); err != nil {
+			failed++
+			fmt.Printf("%3d. %s  FAILED: %v\n", i+1, name, err)
+			for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+				fmt.Printf("       %s\n", line)
+			}
+			continue
+		}
+		fmt.Printf("%3d. %s  ok\n", i+1, name)
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d go.mod files updated, %d tidied, %d failed\n", len(applied), len(applied)-failed, failed)
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("go mod tidy failed in %d modules", failed)
+	}
+
+	return nil
+}
+
+// goModTidy runs go mod tidy in dir outside any workspace, resolving modules
+// through proxy. It returns the combined output.
+func goModTidy(dir, proxy string) (string, error) {
+	c := exec.Command("go", "mod", "tidy")
+	c.Dir = dir
+	c.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOPROXY="+proxy)
+	out, err := c.CombinedOutput()
+	return string(out), err
+}
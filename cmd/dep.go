@@ -15,11 +15,21 @@ var depCmd = &cobra.Command{
 	Short: "Filter repos by dependency",
 	Long: `Filter repositories by dependency on a specific module.
 
-Lists all repositories that depend on the specified Go module path.
+Lists all repositories that depend on the specified Go module path, with the
+required version. The module path may be a pattern where "..." matches any
+string (golang.org/x/... matches golang.org/x and all modules below it), and
+may be followed by a version constraint after @:
+
+  @v1.4.0              exactly v1.4.0
+  @<v1.4.0             comparisons: <, <=, >, >=, =, !=
+  @>=v1.2.0,<v1.4.0    all comma-separated conditions must hold
+  @v1.2.x              any v1.2 version (also @v1.2, @v1.x, @v1)
 
 Examples:
   gitscan dep github.com/grokify/mogo ~/go/src
-  gitscan dep github.com/spf13/cobra ~/go/src -r`,
+  gitscan dep github.com/spf13/cobra ~/go/src -r
+  gitscan dep 'golang.org/x/net@<v0.23.0' ~/go/src -r
+  gitscan dep 'github.com/grokify/...' ~/go/src`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runDep,
 }
@@ -31,8 +41,11 @@ func init() {
 }
 
 func runDep(cmd *cobra.Command, args []string) error {
-	// Parse module path from first argument
-	depFilter := args[0]
+	// Parse module path and version constraint from first argument
+	query, err := scanner.ParseDepQuery(args[0])
+	if err != nil {
+		return err
+	}
 
 	// Get directory from second argument or flag
 	var scanDir string
@@ -96,8 +109,8 @@ func runDep(cmd *cobra.Command, args []string) error {
 	rowNum := 0
 
 	for _, result := range results {
-		hasDep := result.HasDependency(depFilter)
-		if !hasDep {
+		matches := result.MatchDependencies(query)
+		if len(matches) == 0 {
 			continue
 		}
 		depMatchCount++
@@ -108,12 +121,22 @@ func runDep(cmd *cobra.Command, args []string) error {
 		} else {
 			fmt.Printf("%3d. %-*s  [%s]\n", rowNum, maxNameLen, result.Name, result.ModuleName)
 		}
+		for _, m := range matches {
+			line := fmt.Sprintf("       %s %s", m.Module, m.Version)
+			if m.Indirect {
+				line += " (indirect)"
+			}
+			if m.Label() != result.Name {
+				line += " in " + m.Label()
+			}
+			fmt.Println(line)
+		}
 	}
 
 	// Summary
 	fmt.Println()
 	fmt.Println("----------------------------------------")
-	fmt.Printf("Summary: %d repos scanned, %d depend on %s\n", totalRepos, depMatchCount, depFilter)
+	fmt.Printf("Summary: %d repos scanned, %d depend on %s\n", totalRepos, depMatchCount, query)
 
 	return nil
 }
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change.
+const diffContextLines = 3
+
+// unifiedDiff returns a unified diff of a and b labeled with name, or ""
+// if they have the same lines. It uses a simple LCS line diff, which is fine
+// for small files like go.mod.
+func unifiedDiff(name string, a, b []byte) string {
+	oldLines := splitLines(string(a))
+	newLines := splitLines(string(b))
+
+	// lcs[i][j] is the length of the longest common subsequence of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Walk the table to produce an edit script
+	type op struct {
+		kind byte // ' ', '-', '+'
+		text string
+	}
+	var ops []op
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			ops = append(ops, op{' ', oldLines[i]})
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', oldLines[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', newLines[j]})
+			j++
+		}
+	}
+
+	if !slices.ContainsFunc(ops, func(o op) bool { return o.kind != ' ' }) {
+		return ""
+	}
+
+	// Group the edit script into hunks with surrounding context
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", name, name)
+
+	oldLine, newLine := 1, 1
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			oldLine++
+			newLine++
+			k++
+			continue
+		}
+
+		// Find the end of this hunk: stop at a run of unchanged lines longer than twice the context
+		start := max(k-diffContextLines, 0)
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContextLines {
+				end = min(end+diffContextLines, len(ops))
+				break
+			}
+			end = run
+		}
+
+		hunkOld, hunkNew := oldLine-(k-start), newLine-(k-start)
+		var oldCount, newCount int
+		var body strings.Builder
+		for _, o := range ops[start:end] {
+			if o.kind != '+' {
+				oldCount++
+			}
+			if o.kind != '-' {
+				newCount++
+			}
+			body.WriteByte(o.kind)
+			body.WriteString(o.text)
+			body.WriteByte('\n')
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		sb.WriteString(body.String())
+
+		oldLine, newLine = hunkOld+oldCount, hunkNew+newCount
+		k = end
+	}
+
+	return sb.String()
+}
+
+// hunkRange formats the start,count range of a hunk header. An empty range
+// starts at the line before it, as in GNU diff (e.g. "0,0" for an empty file).
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits s into lines without trailing newlines.
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	const lines = "a\nb\nc\nd\ne\n"
+	tests := []struct {
+		name string
+		a, b string
+		want string // Hunks after the file header
+	}{
+		{"both empty", "", "", ""},
+		{"no change", lines, lines, ""},
+		{"empty old", "", "a\nb\n", "@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"empty new", "a\nb\n", "", "@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"insert at start", lines, "x\n" + lines, "@@ -1,3 +1,4 @@\n+x\n a\n b\n c\n"},
+		{"delete at start", lines, "b\nc\nd\ne\n", "@@ -1,4 +1,3 @@\n-a\n b\n c\n d\n"},
+		{"insert at end", lines, lines + "x\n", "@@ -3,3 +3,4 @@\n c\n d\n e\n+x\n"},
+		{"delete at end", lines, "a\nb\nc\nd\n", "@@ -2,4 +2,3 @@\n b\n c\n d\n-e\n"},
+		{
+			"separate hunks",
+			"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n",
+			"A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nK\n",
+			"@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n@@ -8,4 +8,4 @@\n h\n i\n j\n-k\n+K\n",
+		},
+		{
+			"merged hunk",
+			"a\nb\nc\nd\ne\nf\ng\n",
+			"A\nb\nc\nd\ne\nf\nG\n",
+			"@@ -1,7 +1,7 @@\n-a\n+A\n b\n c\n d\n e\n f\n-g\n+G\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want != "" {
+				want = "--- a/go.mod\n+++ b/go.mod\n" + want
+			}
+			if got := unifiedDiff("go.mod", []byte(tt.a), []byte(tt.b)); got != want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	execFilter   repoFilter
+	execParallel int
+	execOrdered  bool
+	execQuiet    bool
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [directory] -- <command> [args...]",
+	Short: "Run a command in each selected repo",
+	Long: `Run a command in the root directory of each repo selected by the --since,
+--dep, --transitive, --unpushed and seed filters (the same filters as order),
+with all repos selected by default.
+
+Commands run in parallel (--parallel, default: number of CPUs). Each repo's
+output is captured and printed when its command finishes, followed by a summary
+of exit statuses. The command is run directly, not through a shell; use
+"sh -c '...'" for pipes or shell syntax.
+
+Use --ordered to run repos in dependency order: a repo only starts once the
+selected repos it depends on have succeeded, and the dependents of a failed
+repo are skipped.
+
+Examples:
+  gitscan exec ~/go/src/github.com/grokify -- git status --short
+  gitscan exec -s 7d -t --ordered ~/go/src -- go test ./...
+  gitscan exec --dep github.com/foo/bar -p 4 ~/go/src -- go get github.com/foo/bar@latest
+  gitscan exec -u ~/go/src -- sh -c 'git log --oneline @{u}.. | wc -l'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+func init() {
+	execCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	execFilter.addFlags(execCmd)
+	execCmd.Flags().IntVarP(&execParallel, "parallel", "p", 0, "Number of commands to run in parallel (default: number of CPUs)")
+	execCmd.Flags().BoolVar(&execOrdered, "ordered", false, "Run repos after their dependencies, skipping dependents of failed repos")
+	execCmd.Flags().BoolVarP(&execQuiet, "quiet", "q", false, "Only print the output of failed commands")
+	execCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Include nested go.mod modules in the dependency graph")
+	execCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(execCmd)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 || dash == len(args) {
+		return fmt.Errorf("command required\nUsage: gitscan exec [directory] -- <command> [args...]")
+	}
+	if dash > 1 {
+		return fmt.Errorf("unexpected arguments before --: %s", strings.Join(args[1:dash], " "))
+	}
+	argv := args[dash:]
+
+	scanDir, err := scanDirArg(args[:dash], 0, "gitscan exec [directory] -- <command> [args...]")
+	if err != nil {
+		return err
+	}
+	if execParallel < 0 {
+		return fmt.Errorf("invalid --parallel %d, must be at least 1", execParallel)
+	}
+	if err := execFilter.validate(); err != nil {
+		return err
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		GitBackend: createGitBackend(useGoGit),
+	}
+	execFilter.scanOptions(&opts)
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	selected, err := execFilter.selectRepos(results)
+	if err != nil {
+		return err
+	}
+	selected = execFilter.filterUnpushed(selected)
+	if len(selected) == 0 {
+		fmt.Println("No repos selected")
+		return nil
+	}
+
+	if execOrdered {
+		sorted, cycles := scanner.TopologicalSort(selected)
+		printCycles(cycles)
+		selected = append(sorted, nonGoRepos(selected)...)
+	}
+
+	fmt.Printf("\nRunning `%s` in %d repos:\n\n", strings.Join(argv, " "), len(selected))
+
+	execOpts := scanner.ExecOptions{
+		Workers: execParallel,
+		Ordered: execOrdered,
+	}
+	execResults := scanner.ExecRepos(selected, results, argv, execOpts, func(completed, total int, res scanner.ExecResult) {
+		var status string
+		switch {
+		case res.Skipped():
+			status = fmt.Sprintf("skipped (%s failed)", res.SkippedBy)
+		case res.ExitCode == 0:
+			status = fmt.Sprintf("ok (%s)", res.Duration.Round(time.Millisecond))
+		case res.ExitCode > 0:
+			status = fmt.Sprintf("FAILED exit %d (%s)", res.ExitCode, res.Duration.Round(time.Millisecond))
+		default:
+			status = fmt.Sprintf("FAILED: %v", res.Err)
+		}
+		fmt.Printf("[%d/%d] %s  %s\n", completed, total, res.Repo.Name, status)
+
+		output := strings.TrimRight(string(res.Output), "\n")
+		if output == "" || (execQuiet && !res.Failed()) {
+			return
+		}
+		for line := range strings.SplitSeq(output, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	})
+
+	var failed, skipped []string
+	for _, res := range execResults {
+		switch {
+		case res.Failed():
+			failed = append(failed, res.Repo.Name)
+		case res.Skipped():
+			skipped = append(skipped, res.Repo.Name)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d repos, %d succeeded, %d failed, %d skipped\n",
+		len(execResults), len(execResults)-len(failed)-len(skipped), len(failed), len(skipped))
+	if len(failed) > 0 {
+		fmt.Printf("Failed: %s\n", strings.Join(failed, ", "))
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("Skipped: %s\n", strings.Join(skipped, ", "))
+	}
+	if len(failed) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("command failed in %d repos", len(failed))
+	}
+
+	return nil
+}
+
+// nonGoRepos returns the repos without any go.mod, which TopologicalSort omits.
+func nonGoRepos(results []scanner.RepoResult) []scanner.RepoResult {
+	var repos []scanner.RepoResult
+	for _, r := range results {
+		if len(r.GoMods()) == 0 {
+			repos = append(repos, r)
+		}
+	}
+	return repos
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/grokify/mogo/fmt/progress"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fetchFilter   repoFilter
+	fetchParallel int
+)
+
+var fetchCmd = &cobra.Command{
+	Use:   "fetch [directory]",
+	Short: "Fetch all remotes of each repo and report moved branches",
+	Long: `Fetch all remotes of each selected git repo in parallel, pruning deleted
+branches, then report the repos whose remote-tracking branches moved.
+
+Unpushed status is only as fresh as the last fetch; use this command (or --fetch
+on scans and order) to bring it up to date. Repos are selected by the same
+filters as order, with all repos selected by default.
+
+Each repo is fetched with a time limit (--timeout). Fetching does not prompt
+for credentials: remotes needing input fail instead of blocking. The go-git
+backend only fetches remotes that need no authentication (e.g., file://).
+
+Examples:
+  gitscan fetch ~/go/src/github.com/grokify
+  gitscan fetch -p 16 --timeout 30s ~/go/src
+  gitscan fetch -s 30d ~/go/src   # Only repos modified in the last 30 days`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFetch,
+}
+
+func init() {
+	fetchCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	fetchFilter.addFlags(fetchCmd)
+	fetchCmd.Flags().IntVarP(&fetchParallel, "parallel", "p", 8, "Number of repos to fetch in parallel")
+	fetchCmd.Flags().DurationVar(&fetchTimeout, "timeout", scanner.DefaultFetchTimeout, "Time limit for fetching each repo")
+	fetchCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(fetchCmd)
+}
+
+func runFetch(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan fetch [directory]")
+	if err != nil {
+		return err
+	}
+	if fetchParallel < 1 {
+		return fmt.Errorf("invalid --parallel %d, must be at least 1", fetchParallel)
+	}
+	if err := fetchFilter.validate(); err != nil {
+		return err
+	}
+
+	backend := createGitBackend(useGoGit)
+	opts := scanner.ScanOptions{GitBackend: backend}
+	fetchFilter.scanOptions(&opts)
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	selected, err := fetchFilter.selectRepos(results)
+	if err != nil {
+		return err
+	}
+	selected = fetchFilter.filterUnpushed(selected)
+
+	fmt.Println()
+	renderer := progress.NewSingleStageRenderer(os.Stdout).WithBarWidth(progressBarWidth)
+	fetchOpts := scanner.FetchOptions{
+		Workers: fetchParallel,
+		Timeout: fetchTimeout,
+	}
+	fetched := scanner.FetchRepos(selected, backend, fetchOpts, func(current, total int, name string) {
+		renderer.Update(current, total, name)
+	})
+	renderer.Done("Fetch complete!")
+
+	var (
+		moved  []scanner.FetchResult
+		failed []scanner.FetchResult
+	)
+	for _, f := range fetched {
+		if len(f.Changes) > 0 {
+			moved = append(moved, f)
+		}
+		if f.Err != nil {
+			failed = append(failed, f)
+		}
+	}
+
+	if len(moved) > 0 {
+		fmt.Println("\nRepos with moved remote-tracking branches:")
+		fmt.Println("------------------------------------------")
+		for i, f := range moved {
+			fmt.Printf("%3d. %s\n", i+1, f.Repo.Name)
+			maxRefLen := 0
+			for _, c := range f.Changes {
+				maxRefLen = max(maxRefLen, len(c.Ref))
+			}
+			for _, c := range f.Changes {
+				switch {
+				case c.Old == "":
+					fmt.Printf("       %-*s  (new) %s\n", maxRefLen, c.Ref, shortHash(c.New))
+				case c.New == "":
+					fmt.Printf("       %-*s  (deleted, was %s)\n", maxRefLen, c.Ref, shortHash(c.Old))
+				default:
+					fmt.Printf("       %-*s  %s..%s\n", maxRefLen, c.Ref, shortHash(c.Old), shortHash(c.New))
+				}
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		fmt.Println("\nFetch errors:")
+		for _, f := range failed {
+			fmt.Printf("  %s (%s): %v\n", f.Repo.Name, f.Duration.Round(time.Millisecond), f.Err)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d repos fetched, %d with moved branches, %d failed\n", len(fetched), len(moved), len(failed))
+	if len(failed) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("fetch failed for %d repos", len(failed))
+	}
+
+	return nil
+}
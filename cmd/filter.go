@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+// repoFilter selects repos by modification time, dependency, and push state.
+// It backs the selection flags shared by order, work, and other subcommands.
+//
+// The seed flags choose the starting repos from git state or explicit names
+// (OR logic between seed flags); --since and --dep further restrict them, and
+// --transitive expands the result with transitive dependents.
+type repoFilter struct {
+	since      string
+	dep        string
+	transitive bool
+	unpushed   bool
+
+	seeds        []string
+	seedUnpushed bool
+	seedUntagged bool
+	seedDep      string
+
+	sinceDuration time.Duration
+	depQuery      scanner.DepQuery
+	seedDepQuery  scanner.DepQuery
+}
+
+// addFlags registers the selection flags on cmd.
+func (f *repoFilter) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.since, "since", "s", "", "Filter repos modified within duration (e.g., 7d, 14d, 2w, 1m)")
+	cmd.Flags().StringVar(&f.dep, "dep", "", "Filter repos that depend on a module, e.g. golang.org/x/net@<v0.23.0 (AND logic with --since)")
+	cmd.Flags().BoolVarP(&f.transitive, "transitive", "t", false, "Include repos that transitively depend on selected repos")
+	cmd.Flags().BoolVarP(&f.unpushed, "unpushed", "u", false, "Only include repos with uncommitted changes or unpushed commits")
+	cmd.Flags().StringSliceVar(&f.seeds, "seed", nil, "Seed the selection with these repos (comma-separated names)")
+	cmd.Flags().BoolVar(&f.seedUnpushed, "seed-unpushed", false, "Seed the selection with repos that have uncommitted changes or unpushed commits")
+	cmd.Flags().BoolVar(&f.seedUntagged, "seed-untagged", false, "Seed the selection with repos that have commits since their latest semver tag")
+	cmd.Flags().StringVar(&f.seedDep, "seed-dep", "", "Seed the selection with repos that depend on a module")
+}
+
+// hasSeeds returns true if any seed flag is set.
+func (f *repoFilter) hasSeeds() bool {
+	return len(f.seeds) > 0 || f.seedUnpushed || f.seedUntagged || f.seedDep != ""
+}
+
+// isSeed returns true if the repo matches any seed flag.
+func (f *repoFilter) isSeed(r scanner.RepoResult) bool {
+	return slices.Contains(f.seeds, r.Name) ||
+		(f.seedUnpushed && r.NeedsPush()) ||
+		(f.seedUntagged && r.HasUnreleasedCommits()) ||
+		(f.seedDep != "" && len(r.MatchDependencies(f.seedDepQuery)) > 0)
+}
+
+// validate parses the since duration and the dependency queries (module
+// paths or patterns with optional version constraints, as accepted by dep).
+// It must be called before selectRepos.
+func (f *repoFilter) validate() error {
+	var err error
+	if f.dep != "" {
+		if f.depQuery, err = scanner.ParseDepQuery(f.dep); err != nil {
+			return err
+		}
+	}
+	if f.seedDep != "" {
+		if f.seedDepQuery, err = scanner.ParseDepQuery(f.seedDep); err != nil {
+			return err
+		}
+	}
+	if f.since == "" {
+		return nil
+	}
+	d, err := parseDuration(f.since)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %v\nValid formats: 7d (days), 2w (weeks), 1m (months), 24h (hours)", f.since, err)
+	}
+	f.sinceDuration = d
+	return nil
+}
+
+// scanOptions enables the scan checks needed to evaluate the filter.
+func (f *repoFilter) scanOptions(opts *scanner.ScanOptions) {
+	if f.since != "" {
+		opts.CheckModTime = true
+	}
+	if f.unpushed || f.seedUnpushed {
+		opts.CheckUnpushed = true
+	}
+	if f.seedUntagged {
+		opts.CheckTags = true
+	}
+}
+
+// selectRepos selects the seed repos (all repos when no seed flag is set),
+// applies the --since and --dep filters with AND logic, then expands the
+// selection with transitive dependents when --transitive is set. With no seed
+// or filter flags, all results are selected. The --unpushed filter is applied
+// separately by filterUnpushed so callers can apply it after ordering.
+// Returns an error if a --seed name is not a scanned repo.
+func (f *repoFilter) selectRepos(results []scanner.RepoResult) ([]scanner.RepoResult, error) {
+	for _, name := range f.seeds {
+		if !slices.ContainsFunc(results, func(r scanner.RepoResult) bool { return r.Name == name }) {
+			return nil, fmt.Errorf("seed repo %q not found in scanned directory", name)
+		}
+	}
+	if !f.hasSeeds() && f.sinceDuration == 0 && f.dep == "" {
+		return results, nil
+	}
+
+	var filtered []scanner.RepoResult
+	for _, r := range results {
+		if f.hasSeeds() && !f.isSeed(r) {
+			continue
+		}
+		if f.sinceDuration > 0 && !r.ModifiedSince(f.sinceDuration) {
+			continue
+		}
+		if f.dep != "" && len(r.MatchDependencies(f.depQuery)) == 0 {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+
+	criteria := f.describe()
+	if f.transitive && len(filtered) > 0 {
+		// Expand to include transitive dependents
+		expanded := scanner.GetTransitiveDependents(filtered, results)
+		fmt.Fprintf(statusOut, "Found %d repos %s, expanded to %d with transitive dependents\n",
+			len(filtered), criteria, len(expanded))
+		return expanded, nil
+	}
+
+	fmt.Fprintf(statusOut, "Filtered to %d repos %s\n", len(filtered), criteria)
+	return filtered, nil
+}
+
+// filterUnpushed keeps only repos with uncommitted changes or unpushed
+// commits when --unpushed is set.
+func (f *repoFilter) filterUnpushed(results []scanner.RepoResult) []scanner.RepoResult {
+	if !f.unpushed {
+		return results
+	}
+	var unpushed []scanner.RepoResult
+	for _, r := range results {
+		if r.NeedsPush() {
+			unpushed = append(unpushed, r)
+		}
+	}
+	fmt.Fprintf(statusOut, "Filtered to %d repos with unpushed changes\n", len(unpushed))
+	return unpushed
+}
+
+// describe returns a description of the seed, --since and --dep criteria for messages.
+func (f *repoFilter) describe() string {
+	var seedParts []string
+	if len(f.seeds) > 0 {
+		seedParts = append(seedParts, "named "+strings.Join(f.seeds, ", "))
+	}
+	if f.seedUnpushed {
+		seedParts = append(seedParts, "with unpushed changes")
+	}
+	if f.seedUntagged {
+		seedParts = append(seedParts, "with commits since their latest tag")
+	}
+	if f.seedDep != "" {
+		seedParts = append(seedParts, "depending on "+f.seedDep)
+	}
+
+	var parts []string
+	if len(seedParts) > 0 {
+		parts = append(parts, strings.Join(seedParts, " or "))
+	}
+	switch {
+	case f.since != "" && f.dep != "":
+		parts = append(parts, fmt.Sprintf("modified within %s that depend on %s", f.since, f.dep))
+	case f.dep != "":
+		parts = append(parts, fmt.Sprintf("that depend on %s", f.dep))
+	case f.since != "":
+		parts = append(parts, fmt.Sprintf("modified within %s", f.since))
+	}
+	return strings.Join(parts, ", ")
+}
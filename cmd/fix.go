@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixDryRun bool
+	fixYes    bool
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix",
+	Short: "Rewrite go.mod files to fix common issues",
+	Long: `Rewrite go.mod files across repositories to fix issues found by gitscan.
+
+Each fix shows a diff of the planned changes and asks for confirmation before
+writing. Use --dry-run to only show the diffs, or --yes to skip the prompt.`,
+}
+
+func init() {
+	fixCmd.PersistentFlags().BoolVarP(&fixDryRun, "dry-run", "n", false, "Show the diffs without writing any files")
+	fixCmd.PersistentFlags().BoolVarP(&fixYes, "yes", "y", false, "Apply changes without asking for confirmation")
+	rootCmd.AddCommand(fixCmd)
+}
+
+// applyGoModEdits prints each planned edit with a diff, then writes the
+// changed files after confirmation (unless dryRun is set; yes skips the
+// prompt). It returns the edits that were written.
+func applyGoModEdits(edits []scanner.GoModEdit, dryRun, yes bool) ([]scanner.GoModEdit, error) {
+	var changed []scanner.GoModEdit
+	for _, edit := range edits {
+		fmt.Printf("\n%s\n", filepath.Join(edit.Repo, edit.GoModPath))
+		for _, change := range edit.Changes {
+			fmt.Printf("  - %s\n", change)
+		}
+		for _, skipped := range edit.Skipped {
+			fmt.Printf("  - skipped: %s\n", skipped)
+		}
+		if edit.Changed() {
+			changed = append(changed, edit)
+			fmt.Println()
+			fmt.Print(unifiedDiff(filepath.Join(edit.Repo, edit.GoModPath), edit.Original, edit.Updated))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	if len(changed) == 0 {
+		fmt.Println("Nothing to change")
+		return nil, nil
+	}
+	if dryRun {
+		fmt.Printf("Dry run: %d go.mod files would be changed\n", len(changed))
+		return nil, nil
+	}
+	if !yes && !confirm(fmt.Sprintf("Apply changes to %d go.mod files?", len(changed))) {
+		fmt.Println("Aborted, no files changed")
+		return nil, nil
+	}
+
+	for i, edit := range changed {
+		if err := edit.Apply(); err != nil {
+			return changed[:i], fmt.Errorf("updated %d of %d go.mod files, error writing %s: %w", i, len(changed), edit.File, err)
+		}
+	}
+	fmt.Printf("Updated %d go.mod files\n", len(changed))
+
+	return changed, nil
+}
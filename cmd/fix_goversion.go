@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	goversion "go/version"
+	"sort"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixGoVersionFilter    repoFilter
+	fixGoVersionGo        string
+	fixGoVersionToolchain string
+)
+
+var fixGoVersionCmd = &cobra.Command{
+	Use:   "goversion [directory]",
+	Short: "Set the go and toolchain directives of go.mod files",
+	Long: `Raise the go directive of go.mod files to the version given by --go.
+
+go directives already above that version are left unchanged and reported.
+Use --toolchain to also set the toolchain directive (e.g., go1.25.3), or
+--toolchain none to drop it. Without --toolchain, toolchain directives older
+than the new go version are dropped, as the go command does.
+
+Repos are selected by the same filters as order, and --recurse includes nested
+go.mod files. Files are rewritten with the modfile formatter, so comments and
+the layout of other directives are kept.
+
+Examples:
+  gitscan fix goversion --go 1.25 -n ~/go/src         # Show diffs only
+  gitscan fix goversion --go 1.25 -r -s 30d ~/go/src  # Recently modified repos, nested go.mod files
+  gitscan fix goversion --go 1.25 --toolchain none --dep github.com/grokify/mogo ~/go/src`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFixGoVersion,
+}
+
+func init() {
+	fixGoVersionCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	fixGoVersionCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Include nested go.mod files")
+	fixGoVersionFilter.addFlags(fixGoVersionCmd)
+	fixGoVersionCmd.Flags().StringVar(&fixGoVersionGo, "go", "", "Go version for the go directive (e.g., 1.25)")
+	fixGoVersionCmd.Flags().StringVar(&fixGoVersionToolchain, "toolchain", "", "Toolchain directive to set (e.g., go1.25.3), or none to drop it")
+	fixGoVersionCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	_ = fixGoVersionCmd.MarkFlagRequired("go")
+	fixCmd.AddCommand(fixGoVersionCmd)
+}
+
+func runFixGoVersion(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan fix goversion --go <version> [directory]")
+	if err != nil {
+		return err
+	}
+	goVersion := strings.TrimPrefix(fixGoVersionGo, "go")
+	if !goversion.IsValid("go" + goVersion) {
+		return fmt.Errorf("invalid --go %q, expected a Go version such as 1.25", fixGoVersionGo)
+	}
+	toolchain := fixGoVersionToolchain
+	if toolchain != "" && toolchain != "none" {
+		if !strings.HasPrefix(toolchain, "go") {
+			toolchain = "go" + toolchain
+		}
+		if !goversion.IsValid(toolchain) {
+			return fmt.Errorf("invalid --toolchain %q, expected a toolchain such as go1.25.3 or none", fixGoVersionToolchain)
+		}
+		if goversion.Compare(toolchain, "go"+goVersion) < 0 {
+			return fmt.Errorf("--toolchain %s is older than --go %s", toolchain, goVersion)
+		}
+	}
+	if err := fixGoVersionFilter.validate(); err != nil {
+		return err
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		GitBackend: createGitBackend(useGoGit),
+	}
+	fixGoVersionFilter.scanOptions(&opts)
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	selected, err := fixGoVersionFilter.selectRepos(results)
+	if err != nil {
+		return err
+	}
+	selected = fixGoVersionFilter.filterUnpushed(selected)
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	var edits []scanner.GoModEdit
+	for _, r := range selected {
+		repoEdits, err := scanner.SetGoVersion(r, goVersion, toolchain)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		edits = append(edits, repoEdits...)
+	}
+
+	_, err = applyGoModEdits(edits, fixDryRun, fixYes)
+	return err
+}
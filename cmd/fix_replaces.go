@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var fixReplacesCmd = &cobra.Command{
+	Use:   "replaces [directory]",
+	Short: "Remove local replace directives pointing at sibling repos",
+	Long: `Remove local-path replace directives that point at other scanned repos.
+
+For each repo with a replace such as "github.com/grokify/mogo => ../mogo", the
+replace is dropped and the module is required at the latest semver tag in the
+sibling repo's local git repository. Requirements already at or above that
+version are left unchanged. Replaces pointing outside the scan set, within the
+same repo, or at repos without tags are skipped and reported.
+
+Examples:
+  gitscan fix replaces --dry-run ~/go/src    # Show diffs only
+  gitscan fix replaces -r ~/go/src           # Include nested go.mod files
+  gitscan fix replaces -y ~/go/src           # Apply without confirmation`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFixReplaces,
+}
+
+func init() {
+	fixReplacesCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	fixReplacesCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Check nested go.mod files")
+	fixReplacesCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	fixCmd.AddCommand(fixReplacesCmd)
+}
+
+func runFixReplaces(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan fix replaces [directory]")
+	if err != nil {
+		return err
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		CheckTags:  true, // Needed to find the sibling's latest version
+		GitBackend: createGitBackend(useGoGit),
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	var edits []scanner.GoModEdit
+	for _, r := range results {
+		repoEdits, err := scanner.RemoveLocalReplaces(r, results)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		edits = append(edits, repoEdits...)
+	}
+
+	applied, err := applyGoModEdits(edits, fixDryRun, fixYes)
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		fmt.Println("Run `go mod tidy` in each to refresh go.sum.")
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	goversion "go/version"
+	"sort"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var goVersionsMin string
+
+var goVersionsCmd = &cobra.Command{
+	Use:   "goversions [directory]",
+	Short: "Report the distribution of go and toolchain directives",
+	Long: `Collect the go and toolchain directives of every go.mod (root, and nested
+with --recurse), group the modules by go version with their distribution, and
+flag modules below a minimum version (--min).
+
+Examples:
+  gitscan goversions ~/go/src/github.com/grokify
+  gitscan goversions --min 1.23 -r ~/go/src/github.com/grokify`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGoVersions,
+}
+
+func init() {
+	goVersionsCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	goVersionsCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Include nested go.mod files")
+	goVersionsCmd.Flags().StringVar(&goVersionsMin, "min", "", "Flag modules with a go directive below this version (e.g., 1.23)")
+	goVersionsCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(goVersionsCmd)
+}
+
+func runGoVersions(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan goversions [directory]")
+	if err != nil {
+		return err
+	}
+	minVersion := strings.TrimPrefix(goVersionsMin, "go")
+	if minVersion != "" && !goversion.IsValid("go"+minVersion) {
+		return fmt.Errorf("invalid --min %q, expected a Go version such as 1.23", goVersionsMin)
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		GitBackend: createGitBackend(useGoGit),
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	versions := scanner.GoModVersions(results)
+	if len(versions) == 0 {
+		fmt.Println("\nNo go.mod files found")
+		return nil
+	}
+	groups := scanner.GroupByGoVersion(versions)
+
+	maxCount, maxLabelLen := 0, 0
+	for _, g := range groups {
+		maxCount = max(maxCount, len(g.Modules))
+		maxLabelLen = max(maxLabelLen, len(goDirectiveLabel(g.GoVersion)))
+	}
+
+	fmt.Println("\nGo versions (newest first):")
+	fmt.Println("---------------------------")
+	for _, g := range groups {
+		barLen := max(1, len(g.Modules)*progressBarWidth/maxCount)
+		fmt.Printf("  %-*s  %-*s  %3d (%2.0f%%)\n", maxLabelLen, goDirectiveLabel(g.GoVersion),
+			progressBarWidth, strings.Repeat("█", barLen), len(g.Modules), 100*float64(len(g.Modules))/float64(len(versions)))
+		var names []string
+		for _, v := range g.Modules {
+			names = append(names, v.Name())
+		}
+		fmt.Printf("  %*s  %s\n", maxLabelLen, "", strings.Join(names, ", "))
+	}
+
+	// Toolchain distribution
+	toolchains := make(map[string]int)
+	for _, v := range versions {
+		toolchains[v.Toolchain]++
+	}
+	var toolchainNames []string
+	for name := range toolchains {
+		toolchainNames = append(toolchainNames, name)
+	}
+	sort.Slice(toolchainNames, func(i, j int) bool {
+		a, b := toolchainNames[i], toolchainNames[j]
+		if a == "" || b == "" {
+			return b == ""
+		}
+		return goversion.Compare(a, b) > 0
+	})
+	fmt.Println("\nToolchains:")
+	for _, name := range toolchainNames {
+		label := name
+		if label == "" {
+			label = "(none)"
+		}
+		fmt.Printf("  %-12s  %3d\n", label, toolchains[name])
+	}
+
+	var below []scanner.GoModVersion
+	if minVersion != "" {
+		maxNameLen := 0
+		for _, v := range versions {
+			if scanner.GoVersionBelow(v.GoVersion, minVersion) {
+				below = append(below, v)
+				maxNameLen = max(maxNameLen, len(v.Name()))
+			}
+		}
+		if len(below) > 0 {
+			fmt.Printf("\nBelow go %s:\n", minVersion)
+			for i, v := range below {
+				details := goDirectiveLabel(v.GoVersion)
+				if v.Toolchain != "" {
+					details += ", toolchain " + v.Toolchain
+				}
+				fmt.Printf("%3d. %-*s  %s\n", i+1, maxNameLen, v.Name(), details)
+			}
+		}
+	}
+
+	repos := make(map[string]bool)
+	for _, v := range versions {
+		repos[v.Repo.Name] = true
+	}
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	summary := fmt.Sprintf("Summary: %d go.mod files in %d repos, %d go versions", len(versions), len(repos), len(groups))
+	if minVersion != "" {
+		summary += fmt.Sprintf(", %d below go %s", len(below), minVersion)
+	}
+	fmt.Println(summary)
+
+	return nil
+}
+
+// goDirectiveLabel formats a go directive version for display.
+func goDirectiveLabel(goVersion string) string {
+	if goVersion == "" {
+		return "(no go directive)"
+	}
+	return "go " + goVersion
+}
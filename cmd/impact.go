@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact <repo-or-module> [directory]",
+	Short: "Show all repos affected by a change to a repo or module",
+	Long: `Show the full tree of managed repos that transitively depend on a repo or
+module, to estimate the blast radius of a breaking change.
+
+The target is a scanned repo name (e.g., mogo) or a module path. A module owned
+by a scanned repo resolves to that repo; for other modules (e.g., an external
+library), the repos requiring it directly form the first level.
+
+Each dependent is shown under the repo through which it is first reached, with
+its depth and whether it has uncommitted changes or unpushed commits.
+
+Examples:
+  gitscan impact mogo ~/go/src/github.com/grokify
+  gitscan impact github.com/grokify/mogo ~/go/src/github.com/grokify
+  gitscan impact github.com/spf13/cobra -r ~/go/src`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runImpact,
+}
+
+func init() {
+	impactCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	impactCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Include nested go.mod modules in the dependency graph")
+	impactCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(impactCmd)
+}
+
+func runImpact(cmd *cobra.Command, args []string) error {
+	target := args[0]
+	scanDir, err := scanDirArg(args, 1, "gitscan impact <repo-or-module> [directory]")
+	if err != nil {
+		return err
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:       recurse,
+		CheckUnpushed: true,
+		GitBackend:    createGitBackend(useGoGit),
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	im, ok := scanner.GetImpact(target, results)
+	if !ok {
+		return fmt.Errorf("%q is not a scanned repo or a module required by one", target)
+	}
+
+	root := impactRoot(im)
+	if im.Repo != "" && im.Module != "" {
+		fmt.Printf("\nImpact of %s [%s]:\n\n", im.Repo, im.Module)
+	} else {
+		fmt.Printf("\nImpact of %s:\n\n", root)
+	}
+
+	if len(im.Dependents) == 0 {
+		fmt.Println("No managed repos depend on it")
+		return nil
+	}
+
+	// Build the tree from each dependent's path
+	children := make(map[string][]scanner.Dependent)
+	maxNameLen := 0
+	for _, d := range im.Dependents {
+		parent := d.Path[len(d.Path)-2]
+		children[parent] = append(children[parent], d)
+		maxNameLen = max(maxNameLen, 4*d.Depth+len(d.Repo.Name))
+	}
+
+	fmt.Println(root)
+	var (
+		printTree  func(parent, prefix string)
+		dirty      int
+		unpushed   int
+		direct     int
+		transitive int
+	)
+	printTree = func(parent, prefix string) {
+		kids := children[parent]
+		for i, d := range kids {
+			branch, next := "├── ", "│   "
+			if i == len(kids)-1 {
+				branch, next = "└── ", "    "
+			}
+
+			var flags []string
+			if d.Repo.HasUncommittedChanges {
+				flags = append(flags, "uncommitted")
+				dirty++
+			}
+			if d.Repo.HasUnpushedCommits {
+				flags = append(flags, "unpushed")
+				unpushed++
+			}
+			if d.Depth == 1 {
+				direct++
+			} else {
+				transitive++
+			}
+
+			line := prefix + branch + d.Repo.Name
+			details := fmt.Sprintf("depth %d", d.Depth)
+			if also := slices.DeleteFunc(slices.Clone(d.DependsOn), func(dep string) bool { return dep == parent }); len(also) > 0 {
+				details += fmt.Sprintf(", also via: %s", strings.Join(also, ", "))
+			}
+			flagStr := ""
+			if len(flags) > 0 {
+				flagStr = fmt.Sprintf("  [%s]", strings.Join(flags, ", "))
+			}
+			fmt.Printf("%-*s  (%s)%s\n", maxNameLen, line, details, flagStr)
+
+			printTree(d.Repo.Name, prefix+next)
+		}
+	}
+	printTree(root, "")
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d dependents (%d direct, %d transitive), max depth %d\n",
+		len(im.Dependents), direct, transitive, im.MaxDepth())
+	fmt.Printf("Dirty: %d with uncommitted changes, %d with unpushed commits\n", dirty, unpushed)
+
+	return nil
+}
+
+// impactRoot returns the name of the tree root: the target repo, or the
+// module path for modules not owned by a scanned repo.
+func impactRoot(im scanner.Impact) string {
+	if im.Repo != "" {
+		return im.Repo
+	}
+	return im.Target
+}
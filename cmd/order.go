@@ -1,20 +1,22 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/grokify/gitscan/scanner"
-	"github.com/grokify/mogo/fmt/progress"
 	"github.com/spf13/cobra"
 )
 
 var (
-	orderSinceStr     string
-	includeTransitive bool
-	unpushedOnly      bool
+	orderFilter    repoFilter
+	showStale      bool
+	orderWorkspace bool
+	orderFormat    string
+	orderWaves     bool
 )
 
 var orderCmd = &cobra.Command{
@@ -25,18 +27,54 @@ Repos with no internal dependencies are listed first, then repos that depend on
 This helps determine the correct order to update and release Go modules.
 
 When using --since with --transitive, also includes repos that transitively depend
-on modified repos (even if they weren't directly modified).
+on modified repos (even if they weren't directly modified). Use --dep to select
+repos that depend on a module (AND logic with --since).
 
-Use --unpushed to only show repos with uncommitted changes or unpushed commits.`,
+Use --unpushed to only show repos with uncommitted changes or unpushed commits,
+and --fetch to fetch all remotes first so unpushed status is up to date.
+
+Use the seed flags to choose the starting repos from git state instead of (or in
+addition to) modification time: --seed names repos explicitly, --seed-unpushed
+selects repos with uncommitted changes or unpushed commits, --seed-untagged
+selects repos with commits since their latest semver tag (or no tag at all), and
+--seed-dep selects repos depending on a module. Seeds from several flags are
+combined (OR logic), --since and --dep further restrict them, and --transitive
+adds their transitive dependents.
+
+Use --recurse to include nested go.mod modules (e.g., repo/v2 or repo/tools) in
+the dependency graph. A repo depends on another if any of its modules requires
+any module of the other, and the modules of multi-module repos are listed in
+their own dependency order.
+
+Use --workspace to treat repos developed together in a go.work workspace (the
+scan root's go.work, or a repo's go.work that uses sibling repos) as a single
+unit that is ordered and released together.
+
+Use --stale to flag repos that require an older version of a managed dependency
+than the latest semver tag in the dependency's local git repository.
+
+Use --waves to group repos into numbered release waves: every repo in a wave
+only depends on repos in earlier waves, so the repos of a wave can be released
+concurrently. The number of waves is the length of the critical path.
+
+Circular dependencies are reported as explicit paths (a -> b -> a) with the
+go.mod requirement responsible for each step. Repos in a cycle are listed
+together, and repos depending on them are still ordered after them.
+
+Use --format json for machine-readable output; progress messages then go to stderr.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runOrder,
 }
 
 func init() {
 	orderCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
-	orderCmd.Flags().StringVarP(&orderSinceStr, "since", "s", "", "Filter repos modified within duration (e.g., 7d, 14d, 2w, 1m)")
-	orderCmd.Flags().BoolVarP(&includeTransitive, "transitive", "t", false, "Include repos that transitively depend on modified repos")
-	orderCmd.Flags().BoolVarP(&unpushedOnly, "unpushed", "u", false, "Only show repos with uncommitted changes or unpushed commits")
+	orderFilter.addFlags(orderCmd)
+	orderCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Include nested go.mod modules in the dependency graph")
+	orderCmd.Flags().BoolVarP(&orderWorkspace, "workspace", "w", false, "Treat repos in the same go.work workspace as a single unit")
+	orderCmd.Flags().BoolVar(&showStale, "stale", false, "Flag requirements older than the dependency's latest local tag")
+	orderCmd.Flags().BoolVar(&orderWaves, "waves", false, "Group repos into release waves that only depend on earlier waves")
+	orderCmd.Flags().StringVarP(&orderFormat, "format", "f", "text", "Output format: text or json")
+	addFetchFlags(orderCmd)
 	orderCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI (pure Go, no process spawning)")
 	rootCmd.AddCommand(orderCmd)
 }
@@ -51,124 +89,283 @@ func runOrder(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("directory path required\nUsage: gitscan order [directory] or gitscan order -d <directory>")
 	}
 
-	// Parse since duration
-	var sinceDuration time.Duration
-	if orderSinceStr != "" {
-		var err error
-		sinceDuration, err = parseDuration(orderSinceStr)
-		if err != nil {
-			return fmt.Errorf("invalid duration %q: %v\nValid formats: 7d (days), 2w (weeks), 1m (months), 24h (hours)", orderSinceStr, err)
-		}
-	}
-
-	// Resolve path
-	absPath, err := resolvePath(dirPath)
-	if err != nil {
+	if err := orderFilter.validate(); err != nil {
 		return err
 	}
-
-	fmt.Printf("Scanning: %s\n", absPath)
-
-	// Count directories first
-	total, err := scanner.CountDirectories(absPath)
-	if err != nil {
-		return fmt.Errorf("error counting directories: %w", err)
+	if orderFormat != "text" && orderFormat != "json" {
+		return fmt.Errorf("invalid format %q, must be 'text' or 'json'", orderFormat)
 	}
-	fmt.Printf("Found %d directories to scan\n\n", total)
-
-	// Progress renderer
-	renderer := progress.NewSingleStageRenderer(os.Stdout).WithBarWidth(progressBarWidth)
-
-	// Progress callback
-	progressFn := func(current, total int, name string) {
-		renderer.Update(current, total, name)
+	if orderFormat == "json" {
+		statusOut = os.Stderr
 	}
 
 	opts := scanner.ScanOptions{
-		Recurse:       false,
-		CheckModTime:  true,         // Always need mod time for ordering
-		CheckUnpushed: unpushedOnly, // Only check unpushed if filtering by it
-		GitBackend:    createGitBackend(useGoGit),
+		Recurse:      recurse,
+		CheckModTime: true,      // Always need mod time for ordering
+		CheckTags:    showStale, // Only collect tags if checking for stale pins
+		Fetch:        fetchFirst,
+		FetchTimeout: fetchTimeout,
+		GitBackend:   createGitBackend(useGoGit),
 	}
-	results, err := scanner.ScanDirectoryWithProgress(absPath, progressFn, opts)
+	orderFilter.scanOptions(&opts) // Only check unpushed if filtering by it
+	absPath, results, err := scanWithProgress(dirPath, opts)
 	if err != nil {
-		return fmt.Errorf("error scanning directory: %w", err)
+		return err
 	}
 
-	// Clear the progress line and show completion
-	renderer.Done("Scan complete!")
-
-	// Filter by modification time if specified
+	// Filter by modification time and dependency if specified
 	allResults := results // Keep original for transitive lookup
-	if sinceDuration > 0 {
-		var filtered []scanner.RepoResult
-		for _, r := range results {
-			if r.ModifiedSince(sinceDuration) {
-				filtered = append(filtered, r)
-			}
-		}
+	results, err = orderFilter.selectRepos(results)
+	if err != nil {
+		return err
+	}
 
-		if includeTransitive && len(filtered) > 0 {
-			// Expand to include transitive dependents
-			results = scanner.GetTransitiveDependents(filtered, allResults)
-			fmt.Printf("Found %d repos modified within %s, expanded to %d with transitive dependents\n",
-				len(filtered), orderSinceStr, len(results))
+	// Topological sort, optionally contracting workspaces into units
+	var (
+		sorted    []scanner.RepoResult
+		cycles    []scanner.Cycle
+		unitOf    = make(map[string]scanner.ReleaseUnit) // Repo name -> multi-repo unit
+		waveOf    = make(map[string]int)                 // Repo name -> wave number (with --waves)
+		waveCount int
+	)
+	if orderWorkspace {
+		rootWork, err := scanner.FindGoWork(absPath)
+		if err != nil {
+			return fmt.Errorf("error reading go.work: %w", err)
+		}
+		if rootWork != nil {
+			scanner.ResolveWorkspace(rootWork, allResults)
+		}
+		groups := scanner.WorkspaceGroups(allResults, rootWork)
+		var units []scanner.ReleaseUnit
+		if orderWaves {
+			var levels [][]scanner.ReleaseUnit
+			levels, cycles = scanner.TopologicalLevelsUnits(results, groups)
+			for i, level := range levels {
+				for _, unit := range level {
+					for _, r := range unit.Repos {
+						waveOf[r.Name] = i + 1
+					}
+				}
+				units = append(units, level...)
+			}
+			waveCount = len(levels)
 		} else {
-			results = filtered
-			fmt.Printf("Filtered to %d repos modified within %s\n", len(results), orderSinceStr)
+			units, cycles = scanner.TopologicalSortUnits(results, groups)
+		}
+		for _, unit := range units {
+			for _, r := range unit.Repos {
+				if len(unit.Repos) > 1 {
+					unitOf[r.Name] = unit
+				}
+				sorted = append(sorted, r)
+			}
 		}
+	} else if orderWaves {
+		var levels [][]scanner.RepoResult
+		levels, cycles = scanner.TopologicalLevels(results)
+		for i, level := range levels {
+			for _, r := range level {
+				waveOf[r.Name] = i + 1
+			}
+			sorted = append(sorted, level...)
+		}
+		waveCount = len(levels)
+	} else {
+		sorted, cycles = scanner.TopologicalSort(results)
 	}
 
-	// Topological sort
-	sorted, cycles := scanner.TopologicalSort(results)
+	// Filter to only unpushed repos if requested
+	sorted = orderFilter.filterUnpushed(sorted)
 
-	if len(cycles) > 0 {
-		fmt.Println("\nWarning: Circular dependencies detected:")
-		for _, mod := range cycles {
-			fmt.Printf("  - %s\n", mod)
+	inCycle := make(map[string]bool)
+	for _, c := range cycles {
+		for _, name := range c.Path {
+			inCycle[name] = true
 		}
-		fmt.Println()
 	}
 
-	// Filter to only unpushed repos if requested
-	if unpushedOnly {
-		var unpushed []scanner.RepoResult
-		for _, r := range sorted {
-			if r.NeedsPush() {
-				unpushed = append(unpushed, r)
+	var tagged map[string]scanner.TaggedModule
+	if showStale {
+		// Compare against all scanned repos so pins on unselected repos are also caught
+		tagged = scanner.LatestTaggedModules(allResults)
+	}
+
+	entries := make([]orderEntry, 0, len(sorted))
+	for i, r := range sorted {
+		entry := orderEntry{
+			Position:  i + 1,
+			Name:      r.Name,
+			Module:    r.ModuleName,
+			ModTime:   r.LatestModTime,
+			DependsOn: scanner.GetInternalDeps(r, results),
+			InCycle:   inCycle[r.Name],
+			Wave:      waveOf[r.Name],
+		}
+		if goMods := r.SortedGoMods(); len(goMods) > 1 {
+			for _, gm := range goMods {
+				entry.Modules = append(entry.Modules, gm.ModuleName)
 			}
 		}
-		fmt.Printf("Filtered to %d repos with unpushed changes\n", len(unpushed))
-		sorted = unpushed
+		if unit, ok := unitOf[r.Name]; ok {
+			entry.Workspace = unit.Name
+			entry.InCycle = entry.InCycle || inCycle[unit.Name]
+		}
+		if showStale {
+			for _, pin := range scanner.GetStalePins(r, tagged) {
+				entry.Stale = append(entry.Stale, pin.String())
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if orderFormat == "json" {
+		return printOrderJSON(entries, cycles, waveCount)
 	}
 
+	printCycles(cycles)
+
 	// Calculate max name length for alignment
 	maxNameLen := 0
-	for _, r := range sorted {
-		if len(r.Name) > maxNameLen {
-			maxNameLen = len(r.Name)
+	for _, e := range entries {
+		if len(e.Name) > maxNameLen {
+			maxNameLen = len(e.Name)
 		}
 	}
 
-	fmt.Println("\nUpdate order (dependencies first):")
-	fmt.Println("----------------------------------")
+	if orderWaves {
+		fmt.Println("\nRelease waves (each wave only depends on earlier waves):")
+		fmt.Println("--------------------------------------------------------")
+	} else {
+		fmt.Println("\nUpdate order (dependencies first):")
+		fmt.Println("----------------------------------")
+	}
+
+	staleCount := 0
+	for i, e := range entries {
+		if orderWaves && (i == 0 || e.Wave != entries[i-1].Wave) {
+			fmt.Printf("Wave %d:\n", e.Wave)
+		}
 
-	for i, r := range sorted {
-		internalDeps := scanner.GetInternalDeps(r, results)
 		depStr := ""
-		if len(internalDeps) > 0 {
-			depStr = fmt.Sprintf(" (depends on: %s)", strings.Join(internalDeps, ", "))
+		if len(e.DependsOn) > 0 {
+			depStr = fmt.Sprintf(" (depends on: %s)", strings.Join(e.DependsOn, ", "))
 		}
 
 		modTime := ""
-		if !r.LatestModTime.IsZero() {
-			modTime = r.LatestModTime.Format("2006-01-02 15:04")
+		if !e.ModTime.IsZero() {
+			modTime = e.ModTime.Format("2006-01-02 15:04")
 		}
 
-		fmt.Printf("%3d. %-*s  %s%s\n", i+1, maxNameLen, r.Name, modTime, depStr)
+		unitStr := ""
+		if e.Workspace != "" {
+			unitStr = fmt.Sprintf(" [workspace: %s]", e.Workspace)
+		}
+		if e.InCycle {
+			unitStr += " [cycle]"
+		}
+
+		fmt.Printf("%3d. %-*s  %s%s%s\n", e.Position, maxNameLen, e.Name, modTime, depStr, unitStr)
+
+		if len(e.Modules) > 0 {
+			fmt.Printf("     %-*s  modules: %s\n", maxNameLen, "", strings.Join(e.Modules, ", "))
+		}
+
+		if len(e.Stale) > 0 {
+			staleCount++
+		}
+		for _, pin := range e.Stale {
+			fmt.Printf("     %-*s  stale: %s\n", maxNameLen, "", pin)
+		}
 	}
 
-	fmt.Printf("\nTotal: %d repos in dependency order\n", len(sorted))
+	if orderWaves {
+		fmt.Printf("\nTotal: %d repos in %d waves\n", len(entries), waveCount)
+		fmt.Printf("Critical path: %d waves\n", waveCount)
+	} else {
+		fmt.Printf("\nTotal: %d repos in dependency order\n", len(entries))
+	}
+	if len(cycles) > 0 {
+		fmt.Printf("Cycles: %d circular dependency paths\n", len(cycles))
+	}
+	if showStale {
+		fmt.Printf("Stale: %d repos require older versions than tagged locally\n", staleCount)
+	}
 
 	return nil
 }
+
+// orderEntry is a repo in the update order.
+type orderEntry struct {
+	Position  int       `json:"position"`
+	Name      string    `json:"name"`
+	Module    string    `json:"module,omitempty"`
+	Modules   []string  `json:"modules,omitempty"` // All modules in dependency order (multi-module repos only)
+	ModTime   time.Time `json:"modTime,omitzero"`
+	DependsOn []string  `json:"dependsOn,omitempty"`
+	Workspace string    `json:"workspace,omitempty"`
+	InCycle   bool      `json:"inCycle,omitempty"`
+	Wave      int       `json:"wave,omitempty"`
+	Stale     []string  `json:"stale,omitempty"`
+}
+
+// orderCycle is the JSON form of a scanner.Cycle.
+type orderCycle struct {
+	Path         []string          `json:"path"` // Closed path, e.g. ["a", "b", "a"]
+	Requirements []orderDependency `json:"requirements"`
+}
+
+// orderDependency is the JSON form of a scanner.Dependency.
+type orderDependency struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	GoMod   string `json:"goMod"`
+	Line    int    `json:"line,omitempty"`
+	Module  string `json:"module"`
+	Version string `json:"version"`
+}
+
+// printCycles prints each cycle as a path followed by the go.mod requirement
+// responsible for each step.
+func printCycles(cycles []scanner.Cycle) {
+	if len(cycles) == 0 {
+		return
+	}
+	fmt.Println("\nWarning: Circular dependencies detected:")
+	for _, c := range cycles {
+		fmt.Printf("  - %s\n", c)
+		for _, dep := range c.Edges {
+			fmt.Printf("      %s\n", dep)
+		}
+	}
+	fmt.Println()
+}
+
+// printOrderJSON writes the update order and cycles to stdout as JSON.
+// criticalPath is the number of release waves, or 0 without --waves.
+func printOrderJSON(entries []orderEntry, cycles []scanner.Cycle, criticalPath int) error {
+	out := struct {
+		Repos        []orderEntry `json:"repos"`
+		Cycles       []orderCycle `json:"cycles"`
+		CriticalPath int          `json:"criticalPath,omitempty"`
+	}{Repos: entries, Cycles: []orderCycle{}, CriticalPath: criticalPath}
+
+	for _, c := range cycles {
+		oc := orderCycle{Path: append(append([]string{}, c.Path...), c.Path[0])}
+		for _, dep := range c.Edges {
+			oc.Requirements = append(oc.Requirements, orderDependency{
+				From:    dep.From,
+				To:      dep.To,
+				GoMod:   dep.GoModPath,
+				Line:    dep.Line,
+				Module:  dep.Module,
+				Version: dep.Version,
+			})
+		}
+		out.Cycles = append(out.Cycles, oc)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pullFilter repoFilter
+	pullFFOnly bool
+	pullDryRun bool
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull [directory]",
+	Short: "Fast-forward clean repos that are behind their upstream",
+	Long: `Fast-forward the current branch of each selected repo that is strictly
+behind its upstream, without fetching (use --fetch to fetch all remotes first).
+
+Only repos with no uncommitted changes, no unpushed commits and a configured
+upstream are touched; the others are listed with the reason they were skipped.
+Repos are selected by the same filters as order, with all repos selected by
+default.
+
+Only fast-forward pulls are supported (--ff-only, the default).
+
+Examples:
+  gitscan pull --ff-only ~/go/src/github.com/grokify
+  gitscan pull --fetch ~/go/src/github.com/grokify     # Fetch first
+  gitscan pull -n --fetch ~/go/src/github.com/grokify  # Show what would be updated`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPull,
+}
+
+func init() {
+	pullCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	pullFilter.addFlags(pullCmd)
+	pullCmd.Flags().BoolVar(&pullFFOnly, "ff-only", true, "Only fast-forward (required)")
+	pullCmd.Flags().BoolVarP(&pullDryRun, "dry-run", "n", false, "Show the repos that would be updated without changing them")
+	addFetchFlags(pullCmd)
+	pullCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(pullCmd)
+}
+
+func runPull(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan pull [directory]")
+	if err != nil {
+		return err
+	}
+	if !pullFFOnly {
+		return fmt.Errorf("only fast-forward pulls are supported, --ff-only cannot be disabled")
+	}
+	if err := pullFilter.validate(); err != nil {
+		return err
+	}
+
+	backend := createGitBackend(useGoGit)
+	opts := scanner.ScanOptions{
+		CheckUpstream: true,
+		Fetch:         fetchFirst,
+		FetchTimeout:  fetchTimeout,
+		GitBackend:    backend,
+	}
+	pullFilter.scanOptions(&opts)
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	selected, err := pullFilter.selectRepos(results)
+	if err != nil {
+		return err
+	}
+	selected = pullFilter.filterUnpushed(selected)
+
+	var (
+		behind   []scanner.RepoResult
+		skipped  []scanner.RepoResult
+		upToDate int
+	)
+	maxNameLen := 0
+	for _, r := range selected {
+		if !r.IsGitRepo {
+			continue
+		}
+		maxNameLen = max(maxNameLen, len(r.Name))
+		switch {
+		case r.FastForwardBlocker() != "":
+			skipped = append(skipped, r)
+		case r.Upstream.Behind == 0:
+			upToDate++
+		default:
+			behind = append(behind, r)
+		}
+	}
+
+	var updated, failed int
+	if len(behind) > 0 {
+		if pullDryRun {
+			fmt.Println("\nWould fast-forward:")
+		} else {
+			fmt.Println("\nFast-forwarded:")
+		}
+		for i, r := range behind {
+			u := r.Upstream
+			status := fmt.Sprintf("%s  %d commits from %s", u.Branch, u.Behind, u.Upstream)
+			if !pullDryRun {
+				if err := backend.FastForward(r.Path); err != nil {
+					failed++
+					status = fmt.Sprintf("FAILED: %v", err)
+				} else {
+					updated++
+				}
+			}
+			fmt.Printf("%3d. %-*s  %s\n", i+1, maxNameLen, r.Name, status)
+		}
+	}
+
+	if len(skipped) > 0 {
+		fmt.Println("\nSkipped:")
+		for i, r := range skipped {
+			fmt.Printf("%3d. %-*s  %s\n", i+1, maxNameLen, r.Name, r.FastForwardBlocker())
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	if pullDryRun {
+		fmt.Printf("Dry run: %d repos would be updated, %d up to date, %d skipped\n", len(behind), upToDate, len(skipped))
+		return nil
+	}
+	fmt.Printf("Summary: %d updated, %d up to date, %d skipped, %d failed\n", updated, upToDate, len(skipped), failed)
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("fast-forward failed for %d repos", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pushFilter  repoFilter
+	pushTags    bool
+	pushDryRun  bool
+	pushYes     bool
+	pushTimeout time.Duration
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push [directory]",
+	Short: "Push repos with unpushed commits in dependency order",
+	Long: `Push the current branch of each repo with unpushed commits to its upstream,
+in dependency order (dependencies first): the repos listed by order -u.
+
+Repos are selected by the same filters as order; only repos with commits ahead
+of their upstream are pushed. Repos that cannot be pushed (no upstream, detached
+HEAD, or behind their upstream) are skipped, and so are the repos depending on
+them. If a push fails, the repos depending on it are not pushed, while
+independent repos still are.
+
+The plan is shown and confirmed before pushing; use --dry-run to only show it,
+or --yes to skip the prompt. Use --tags to also push all tags, e.g. new release
+tags, of the repos being pushed; tags of repos without commits to push are not
+pushed.
+
+Examples:
+  gitscan push ~/go/src/github.com/grokify
+  gitscan push --tags -n ~/go/src/github.com/grokify  # Show the plan only
+  gitscan push -s 7d --fetch ~/go/src                 # Fetch first to detect repos behind`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPush,
+}
+
+func init() {
+	pushCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	pushFilter.addFlags(pushCmd)
+	pushCmd.Flags().BoolVar(&pushTags, "tags", false, "Also push all tags")
+	pushCmd.Flags().BoolVarP(&pushDryRun, "dry-run", "n", false, "Show the push plan without pushing")
+	pushCmd.Flags().BoolVarP(&pushYes, "yes", "y", false, "Push without asking for confirmation")
+	pushCmd.Flags().DurationVar(&pushTimeout, "timeout", 2*time.Minute, "Time limit for pushing each repo")
+	addFetchFlags(pushCmd)
+	pushCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(pushCmd)
+}
+
+// pushStep is a repo in the push plan.
+type pushStep struct {
+	repo      scanner.RepoResult
+	skip      string // Reason the repo is not pushed ("" to push)
+	blocksDep bool   // Skipping the repo also skips its dependents
+}
+
+func runPush(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan push [directory]")
+	if err != nil {
+		return err
+	}
+	if err := pushFilter.validate(); err != nil {
+		return err
+	}
+
+	backend := createGitBackend(useGoGit)
+	opts := scanner.ScanOptions{
+		CheckUnpushed: true,
+		CheckUpstream: true,
+		Fetch:         fetchFirst,
+		FetchTimeout:  fetchTimeout,
+		GitBackend:    backend,
+	}
+	pushFilter.scanOptions(&opts)
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	selected, err := pushFilter.selectRepos(results)
+	if err != nil {
+		return err
+	}
+	var unpushed []scanner.RepoResult
+	for _, r := range selected {
+		if r.IsGitRepo && r.NeedsPush() {
+			unpushed = append(unpushed, r)
+		}
+	}
+	sorted, cycles := scanner.TopologicalSort(unpushed)
+	printCycles(cycles)
+	sorted = append(sorted, nonGoRepos(unpushed)...)
+
+	// Plan in dependency order, skipping dependents of repos that cannot be pushed
+	var (
+		steps      []pushStep
+		blockedBy  = make(map[string]string) // Repo -> dependency that is not pushed
+		maxNameLen int
+		toPush     int
+	)
+	for _, r := range sorted {
+		maxNameLen = max(maxNameLen, len(r.Name))
+		step := pushStep{repo: r}
+		if reason := pushBlocker(r); reason != "" {
+			step.skip, step.blocksDep = reason, true
+		} else if dep, ok := blockedBy[r.Name]; ok {
+			step.skip, step.blocksDep = fmt.Sprintf("depends on %s, which is not pushed", dep), true
+		} else if r.Upstream.Ahead == 0 {
+			step.skip = "uncommitted changes only, nothing to push"
+			if pushTags {
+				step.skip += "; tags not pushed"
+			}
+		} else {
+			toPush++
+		}
+		if step.blocksDep {
+			blockDependents(r, results, blockedBy)
+		}
+		steps = append(steps, step)
+	}
+
+	if len(steps) == 0 {
+		fmt.Println("\nNo repos with unpushed commits")
+		return nil
+	}
+
+	fmt.Println("\nPush plan (dependencies first):")
+	fmt.Println("-------------------------------")
+	for i, s := range steps {
+		fmt.Printf("%3d. %-*s  %s\n", i+1, maxNameLen, s.repo.Name, pushStepDetails(s))
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	if toPush == 0 {
+		fmt.Printf("Nothing to push, %d repos skipped\n", len(steps))
+		return nil
+	}
+	if pushDryRun {
+		fmt.Printf("Dry run: %d repos would be pushed, %d skipped\n", toPush, len(steps)-toPush)
+		return nil
+	}
+	prompt := fmt.Sprintf("Push %d repos?", toPush)
+	if pushTags {
+		prompt = fmt.Sprintf("Push %d repos with tags?", toPush)
+	}
+	if !pushYes && !confirm(prompt) {
+		fmt.Println("Aborted, nothing pushed")
+		return nil
+	}
+
+	fmt.Println("\nPushing:")
+	var pushed, failed, skipped int
+	failedDeps := make(map[string]string) // Repo -> dependency whose push failed
+	for i, s := range steps {
+		r := s.repo
+		if s.skip != "" {
+			skipped++
+			continue
+		}
+		if dep, ok := failedDeps[r.Name]; ok {
+			skipped++
+			fmt.Printf("%3d. %-*s  skipped (%s failed)\n", i+1, maxNameLen, r.Name, dep)
+			continue
+		}
+
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+		err := backend.Push(ctx, r.Path, r.Upstream.Remote, r.Upstream.Branch, r.Upstream.RemoteBranch(), pushTags)
+		cancel()
+		if err != nil {
+			failed++
+			fmt.Printf("%3d. %-*s  FAILED: %v\n", i+1, maxNameLen, r.Name, err)
+			blockDependents(r, results, failedDeps)
+			continue
+		}
+		pushed++
+		fmt.Printf("%3d. %-*s  ok (%s)\n", i+1, maxNameLen, r.Name, time.Since(start).Round(time.Millisecond))
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d pushed, %d failed, %d skipped\n", pushed, failed, skipped)
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("push failed for %d repos", failed)
+	}
+
+	return nil
+}
+
+// pushBlocker returns why the repo's unpushed commits cannot be pushed to its
+// upstream, or "" if they can.
+func pushBlocker(r scanner.RepoResult) string {
+	u := r.Upstream
+	switch {
+	case u.Branch == "":
+		return "detached HEAD"
+	case u.Upstream == "":
+		return fmt.Sprintf("no upstream for branch %s", u.Branch)
+	case u.Remote == ".":
+		return fmt.Sprintf("upstream %s is a local branch", u.Upstream)
+	case u.Gone:
+		return fmt.Sprintf("upstream %s is gone", u.Upstream)
+	case u.Behind > 0:
+		return fmt.Sprintf("behind %s by %d commits, pull first", u.Upstream, u.Behind)
+	}
+	return ""
+}
+
+// blockDependents records r as the blocking dependency of every repo that
+// transitively depends on it, unless one is already recorded.
+func blockDependents(r scanner.RepoResult, results []scanner.RepoResult, blockedBy map[string]string) {
+	for _, d := range scanner.GetTransitiveDependents([]scanner.RepoResult{r}, results) {
+		if _, ok := blockedBy[d.Name]; !ok && d.Name != r.Name {
+			blockedBy[d.Name] = r.Name
+		}
+	}
+}
+
+// pushStepDetails describes what will be pushed for a plan step, or why not.
+func pushStepDetails(s pushStep) string {
+	if s.skip != "" {
+		return "skip: " + s.skip
+	}
+	u := s.repo.Upstream
+	details := fmt.Sprintf("%s -> %s  %d commits", u.Branch, u.Upstream, u.Ahead)
+
+	var flags []string
+	if pushTags {
+		flags = append(flags, "tags")
+	}
+	if s.repo.HasUncommittedChanges {
+		flags = append(flags, "uncommitted changes not pushed")
+	}
+	if len(flags) > 0 {
+		details += fmt.Sprintf("  [%s]", strings.Join(flags, ", "))
+	}
+	return details
+}
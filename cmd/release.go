@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseTransitive bool
+	releaseAll        bool
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Plan releases of Go modules across repos",
+}
+
+var releasePlanCmd = &cobra.Command{
+	Use:   "plan [directory]",
+	Short: "List repos needing a release in dependency order with suggested versions",
+	Long: `List repos whose root module has commits since its latest semver tag (or has
+never been tagged), in dependency order, with a suggested next version.
+
+The suggestion follows Conventional Commits for the commits since the tag:
+breaking changes ("feat!:" or a "BREAKING CHANGE:" footer) bump the major
+version, "feat:" bumps the minor version, and anything else bumps the patch
+version. Before v1, breaking changes bump the minor version. Untagged modules
+start at v0.1.0 (or vN.0.0 for /vN module paths).
+
+Use --transitive to also plan patch releases for repos that depend on a repo
+being released, so they can pick up the new version.
+
+Examples:
+  gitscan release plan ~/go/src/github.com/grokify
+  gitscan release plan -t ~/go/src/github.com/grokify     # Include dependents
+  gitscan release plan --all ~/go/src/github.com/grokify  # Show up-to-date repos too`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReleasePlan,
+}
+
+func init() {
+	releasePlanCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	releasePlanCmd.Flags().BoolVarP(&releaseTransitive, "transitive", "t", false, "Plan patch releases for repos depending on repos being released")
+	releasePlanCmd.Flags().BoolVar(&releaseAll, "all", false, "Also list repos that do not need a release")
+	releasePlanCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	releaseCmd.AddCommand(releasePlanCmd)
+	rootCmd.AddCommand(releaseCmd)
+}
+
+func runReleasePlan(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan release plan [directory]")
+	if err != nil {
+		return err
+	}
+
+	backend := createGitBackend(useGoGit)
+	opts := scanner.ScanOptions{
+		CheckTags:  true,
+		GitBackend: backend,
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	sorted, cycles := scanner.TopologicalSort(results)
+	printCycles(cycles)
+
+	// Plan in dependency order so dependents see which dependencies are released
+	var (
+		plans     []scanner.ReleasePlan
+		releasing = make(map[string]bool)
+		planErrs  int
+	)
+	for _, r := range sorted {
+		if r.GoMod == nil || !r.IsGitRepo {
+			continue
+		}
+
+		var releasedDeps []string
+		if releaseTransitive {
+			for _, dep := range scanner.GetInternalDeps(r, results) {
+				if releasing[dep] {
+					releasedDeps = append(releasedDeps, dep)
+				}
+			}
+		}
+
+		plan, err := scanner.PlanRelease(r, backend, releasedDeps)
+		if err != nil {
+			fmt.Printf("Warning: %s: %v\n", r.Name, err)
+			planErrs++
+			continue
+		}
+		if plan.Bump != scanner.BumpNone {
+			releasing[r.Name] = true
+		} else if !releaseAll {
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	maxNameLen, maxVersionLen := 0, len("(none)")
+	for _, p := range plans {
+		maxNameLen = max(maxNameLen, len(p.Repo.Name))
+		maxVersionLen = max(maxVersionLen, len(p.Current))
+	}
+
+	fmt.Println("\nRelease plan (dependencies first):")
+	fmt.Println("----------------------------------")
+
+	counts := make(map[scanner.VersionBump]int)
+	initial := 0
+	for i, p := range plans {
+		current := p.Current
+		if current == "" {
+			current = "(none)"
+		}
+
+		var flags []string
+		if p.NeedsNewPath {
+			flags = append(flags, fmt.Sprintf("requires /%s module path", strings.Split(p.Next, ".")[0]))
+		}
+		if p.Repo.HasUncommittedChanges {
+			flags = append(flags, "uncommitted")
+		}
+		flagStr := ""
+		if len(flags) > 0 {
+			flagStr = fmt.Sprintf("  [%s]", strings.Join(flags, ", "))
+		}
+
+		if p.Bump == scanner.BumpNone {
+			status := "up to date"
+			if slices.Contains(p.Repo.HeadTags, p.Current) {
+				status = "up to date, HEAD tagged " + p.Current
+			}
+			fmt.Printf("%3d. %-*s  %-*s  (%s)%s\n", i+1, maxNameLen, p.Repo.Name, maxVersionLen, current, status, flagStr)
+			continue
+		}
+
+		if p.Current == "" {
+			initial++
+			fmt.Printf("%3d. %-*s  %-*s -> %s  (%s)%s\n", i+1, maxNameLen, p.Repo.Name, maxVersionLen, current, p.Next, p.Reason(), flagStr)
+			continue
+		}
+		counts[p.Bump]++
+		fmt.Printf("%3d. %-*s  %-*s -> %s  (%s: %s)%s\n", i+1, maxNameLen, p.Repo.Name, maxVersionLen, current, p.Next, p.Bump, p.Reason(), flagStr)
+	}
+
+	needRelease := counts[scanner.BumpMajor] + counts[scanner.BumpMinor] + counts[scanner.BumpPatch] + initial
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d repos need a release (%d major, %d minor, %d patch, %d initial)\n",
+		needRelease, counts[scanner.BumpMajor], counts[scanner.BumpMinor], counts[scanner.BumpPatch], initial)
+	if planErrs > 0 {
+		fmt.Printf("Errors: %d repos could not be planned\n", planErrs)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var remotesIssuesOnly bool
+
+var remotesCmd = &cobra.Command{
+	Use:   "remotes [directory]",
+	Short: "List remote URLs and check them against module paths",
+	Long: `List the origin of each git repo and check its remotes.
+
+Remote URLs are normalized so SSH and HTTPS forms of the same repository
+compare equal (git@github.com:grokify/mogo.git and
+https://github.com/grokify/mogo both become github.com/grokify/mogo).
+
+Repos are flagged when:
+  - the go.mod module path (without a /vN suffix) does not match the origin
+  - no remote is configured, or none is named origin
+  - several local directories are clones of the same origin
+
+Other remotes (e.g., upstream of a fork) are listed below the origin.
+
+Examples:
+  gitscan remotes ~/go/src/github.com/grokify
+  gitscan remotes --issues ~/go/src   # Only repos with issues`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRemotes,
+}
+
+func init() {
+	remotesCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	remotesCmd.Flags().BoolVar(&remotesIssuesOnly, "issues", false, "Only show repos with issues")
+	remotesCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(remotesCmd)
+}
+
+func runRemotes(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan remotes [directory]")
+	if err != nil {
+		return err
+	}
+
+	opts := scanner.ScanOptions{
+		CheckRemotes: true,
+		GitBackend:   createGitBackend(useGoGit),
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	statuses := scanner.RemoteStatuses(results)
+	maxNameLen, maxOriginLen := 0, 0
+	for _, s := range statuses {
+		maxNameLen = max(maxNameLen, len(s.Repo.Name))
+		maxOriginLen = max(maxOriginLen, len(remoteOriginLabel(s)))
+	}
+
+	fmt.Println("\nRemotes:")
+	fmt.Println("--------")
+
+	var (
+		shown      int
+		mismatches int
+		noRemote   int
+		duplicates int
+	)
+	for _, s := range statuses {
+		if s.Mismatch {
+			mismatches++
+		}
+		if s.NoRemote || s.NoOrigin {
+			noRemote++
+		}
+		if len(s.Duplicates) > 0 {
+			duplicates++
+		}
+		if remotesIssuesOnly && !s.HasIssues() {
+			continue
+		}
+
+		var issues []string
+		if s.Mismatch {
+			issues = append(issues, "module "+s.Repo.ModuleName)
+		}
+		if s.NoOrigin {
+			issues = append(issues, "no origin")
+		}
+		if len(s.Duplicates) > 0 {
+			issues = append(issues, "same origin as "+strings.Join(s.Duplicates, ", "))
+		}
+		issueStr := ""
+		if len(issues) > 0 {
+			issueStr = fmt.Sprintf("  [%s]", strings.Join(issues, "; "))
+		}
+
+		shown++
+		line := fmt.Sprintf("%3d. %-*s  %-*s%s", shown, maxNameLen, s.Repo.Name, maxOriginLen, remoteOriginLabel(s), issueStr)
+		fmt.Println(strings.TrimRight(line, " "))
+		for _, remote := range s.Repo.Remotes {
+			if remote.Name == "origin" {
+				continue
+			}
+			for _, u := range remote.URLs {
+				fmt.Printf("%s%s: %s\n", strings.Repeat(" ", maxNameLen+7), remote.Name, scanner.NormalizeRemoteURL(u))
+			}
+		}
+	}
+	if shown == 0 {
+		fmt.Println("No repos with remote issues")
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d git repos, %d module mismatches, %d without origin, %d sharing an origin\n",
+		len(statuses), mismatches, noRemote, duplicates)
+
+	return nil
+}
+
+// remoteOriginLabel returns the normalized origin, or why there is none.
+func remoteOriginLabel(s scanner.RemoteStatus) string {
+	switch {
+	case s.NoRemote:
+		return "(no remote)"
+	case s.Origin == "":
+		return "(no origin)"
+	}
+	return s.Origin
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
@@ -17,17 +18,44 @@ const (
 )
 
 var (
-	showClean   bool
-	showSummary bool
-	format      string
+	showClean    bool
+	showSummary  bool
+	showErrors   bool
+	showReplaces bool
+	showWorkDirs bool
+	failOn       []string
+	format       string
 )
 
+// failOnCategories are the issue categories accepted by --fail-on.
+var failOnCategories = []string{"uncommitted", "replace", "local-replace", "missing-replace", "go-work", "mismatch", "error"}
+
 var rootCmd = &cobra.Command{
 	Use:   "gitscan [directory]",
 	Short: "Scan git repositories for common issues",
 	Long: `gitscan scans multiple Git repositories and identifies repos that need attention.
 It helps developers prioritize which repositories to update, commit, and push
 by detecting uncommitted changes, replace directives, and module mismatches.
+Repos that could not be fully analyzed (e.g., permission problems) are reported
+with an "error" issue; use --show-errors to see the details.
+
+Replace directives are classified as local-path (=> ../mogo), fork
+(=> github.com/acme/fork v1.2.3), or version pin. Local-path replaces are
+reported separately, as are those pointing at directories that do not exist.
+Use --fail-on local-replace to exit non-zero only for local-path replaces.
+
+A module path is a "mismatch" when it does not match the repo's location. Major
+version suffixes are allowed (github.com/foo/bar/v2 or gopkg.in/yaml.v3 in a
+directory named bar or yaml), and in GOPATH-style layouts (.../src/github.com/foo/bar)
+the full path below src must match. The expected module path is shown below
+the repo.
+
+A go.work file committed to a repo is reported as a "go.work" issue, since
+workspaces are usually local development setup. A go.work in the scanned
+directory itself is summarized before the results.
+
+Use --fetch to fetch all remotes of each repo first; fetch failures are
+reported as errors. See also: gitscan fetch.
 
 Use subcommands for filtering:
   gitscan since <duration> [dir]   Filter by modification time
@@ -42,8 +70,14 @@ func init() {
 	rootCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
 	rootCmd.Flags().BoolVar(&showClean, "show-clean", false, "Show repos with no issues")
 	rootCmd.Flags().BoolVar(&showSummary, "summary", true, "Show summary at the end")
+	rootCmd.Flags().BoolVar(&showErrors, "show-errors", false, "Show error details for repos that could not be fully analyzed")
+	rootCmd.Flags().BoolVar(&showReplaces, "show-replaces", false, "Show each replace directive with its classification, including nested go.mod files with -r")
+	rootCmd.Flags().BoolVar(&showWorkDirs, "show-workspaces", false, "Show the modules used by each go.work file")
+	rootCmd.Flags().StringSliceVar(&failOn, "fail-on", nil, "Exit non-zero if any repo has these issues: "+strings.Join(failOnCategories, ", "))
 	rootCmd.Flags().StringVarP(&format, "format", "f", "list", "Output format: list or table")
+	rootCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Check nested go.mod files")
 	rootCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI (pure Go, no process spawning)")
+	addFetchFlags(rootCmd)
 }
 
 // Execute runs the root command
@@ -68,6 +102,13 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid format %q, must be 'list' or 'table'", format)
 	}
 
+	// Validate fail-on categories
+	for _, category := range failOn {
+		if !slices.Contains(failOnCategories, category) {
+			return fmt.Errorf("invalid --fail-on category %q, must be one of: %s", category, strings.Join(failOnCategories, ", "))
+		}
+	}
+
 	// Resolve path
 	absPath, err := resolvePath(dirPath)
 	if err != nil {
@@ -92,7 +133,10 @@ func runScan(cmd *cobra.Command, args []string) error {
 	}
 
 	opts := scanner.ScanOptions{
-		GitBackend: createGitBackend(useGoGit),
+		Recurse:      recurse,
+		Fetch:        fetchFirst,
+		FetchTimeout: fetchTimeout,
+		GitBackend:   createGitBackend(useGoGit),
 	}
 	results, err := scanner.ScanDirectoryWithProgress(absPath, progressFn, opts)
 	if err != nil {
@@ -107,6 +151,19 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return results[i].Name < results[j].Name
 	})
 
+	// Summarize the workspace in the scanned directory, if any
+	rootWork, err := scanner.FindGoWork(absPath)
+	if err != nil {
+		fmt.Printf("Warning: error reading go.work: %v\n", err)
+	}
+	if rootWork != nil {
+		scanner.ResolveWorkspace(rootWork, results)
+		fmt.Printf("Workspace: %s uses %d modules (%d scanned repos)\n", rootWork.Path, len(rootWork.Uses), len(rootWork.Repos()))
+		if showWorkDirs {
+			printWorkUses(*rootWork, "  ")
+		}
+	}
+
 	// Calculate max name length for alignment
 	maxNameLen := 0
 	for _, r := range results {
@@ -121,7 +178,12 @@ func runScan(cmd *cobra.Command, args []string) error {
 		reposWithIssues  int
 		uncommittedCount int
 		replaceCount     int
+		localCount       int
+		goWorkCount      int
 		mismatchCount    int
+		errorCount       int
+		failCount        int
+		movedCount       int
 	)
 
 	if format == "table" {
@@ -129,9 +191,14 @@ func runScan(cmd *cobra.Command, args []string) error {
 	}
 
 	rowNum := 0
+	var erroredResults []scanner.RepoResult // Error details deferred until after the table
 	for _, result := range results {
 		totalRepos++
-		hasIssues := result.HasUncommittedChanges || result.HasReplaceDirectives || result.HasModuleMismatch
+		if len(result.RemoteChanges) > 0 {
+			movedCount++
+		}
+		hasIssues := result.HasUncommittedChanges || result.HasReplaceDirectives || result.HasCommittedGoWork() ||
+			result.HasModuleMismatch || result.HasErrors()
 
 		if hasIssues {
 			reposWithIssues++
@@ -141,9 +208,21 @@ func runScan(cmd *cobra.Command, args []string) error {
 			if result.HasReplaceDirectives {
 				replaceCount++
 			}
+			if len(result.LocalReplaces()) > 0 {
+				localCount++
+			}
+			if result.HasCommittedGoWork() {
+				goWorkCount++
+			}
 			if result.HasModuleMismatch {
 				mismatchCount++
 			}
+			if result.HasErrors() {
+				errorCount++
+			}
+			if matchesFailOn(result, failOn) {
+				failCount++
+			}
 		}
 
 		// Show repos with issues, or clean repos if requested
@@ -154,7 +233,29 @@ func runScan(cmd *cobra.Command, args []string) error {
 			} else {
 				internalDeps := scanner.GetInternalDeps(result, results)
 				printResult(rowNum, result, maxNameLen, internalDeps)
+				if showReplaces {
+					printReplaces(result, "       ")
+				}
+				if showWorkDirs && result.GoWork != nil {
+					printWorkUses(*result.GoWork, "       ")
+				}
 			}
+			if showErrors && result.HasErrors() {
+				if format == "table" {
+					erroredResults = append(erroredResults, result)
+				} else {
+					printErrors(result, "       ")
+				}
+			}
+		}
+	}
+
+	if len(erroredResults) > 0 {
+		fmt.Println()
+		fmt.Println("Errors:")
+		for _, result := range erroredResults {
+			fmt.Printf("  %s\n", result.Name)
+			printErrors(result, "    ")
 		}
 	}
 
@@ -163,17 +264,64 @@ func runScan(cmd *cobra.Command, args []string) error {
 		fmt.Println("----------------------------------------")
 		fmt.Printf("Summary: %d repos scanned, %d with issues\n", totalRepos, reposWithIssues)
 		fmt.Printf("  - Uncommitted changes: %d\n", uncommittedCount)
-		fmt.Printf("  - Replace directives:  %d\n", replaceCount)
+		fmt.Printf("  - Replace directives:  %d (%d with local paths)\n", replaceCount, localCount)
+		fmt.Printf("  - Committed go.work:   %d\n", goWorkCount)
 		fmt.Printf("  - Module mismatches:   %d\n", mismatchCount)
+		fmt.Printf("  - Errors:              %d\n", errorCount)
+		if fetchFirst {
+			fmt.Printf("  - Remote updates:      %d (repos with moved branches)\n", movedCount)
+		}
+	}
+
+	if failCount > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d repos have issues matching --fail-on %s", failCount, strings.Join(failOn, ","))
 	}
 
 	return nil
 }
 
+// matchesFailOn returns true if the repo has an issue in any of the given categories.
+func matchesFailOn(r scanner.RepoResult, categories []string) bool {
+	for _, category := range categories {
+		switch category {
+		case "uncommitted":
+			if r.HasUncommittedChanges {
+				return true
+			}
+		case "replace":
+			if r.HasReplaceDirectives {
+				return true
+			}
+		case "local-replace":
+			if len(r.LocalReplaces()) > 0 {
+				return true
+			}
+		case "missing-replace":
+			if len(r.MissingReplaces()) > 0 {
+				return true
+			}
+		case "go-work":
+			if r.HasCommittedGoWork() {
+				return true
+			}
+		case "mismatch":
+			if r.HasModuleMismatch {
+				return true
+			}
+		case "error":
+			if r.HasErrors() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func printTableHeader() {
 	fmt.Println()
-	fmt.Println("| # | Repository | Uncommitted | Replace | Mismatch | Git | go.mod |")
-	fmt.Println("|---|------------|-------------|---------|----------|-----|--------|")
+	fmt.Println("| # | Repository | Uncommitted | Replace | go.work | Mismatch | Error | Git | go.mod |")
+	fmt.Println("|---|------------|-------------|---------|---------|----------|-------|-----|--------|")
 }
 
 func printTableRow(num int, r scanner.RepoResult) {
@@ -187,11 +335,21 @@ func printTableRow(num int, r scanner.RepoResult) {
 		replace = fmt.Sprintf("%d", r.ReplaceCount)
 	}
 
+	goWork := ""
+	if r.HasCommittedGoWork() {
+		goWork = "X"
+	}
+
 	mismatch := ""
 	if r.HasModuleMismatch {
 		mismatch = "X"
 	}
 
+	errCount := ""
+	if r.HasErrors() {
+		errCount = fmt.Sprintf("%d", len(r.Errors))
+	}
+
 	git := "Y"
 	if !r.IsGitRepo {
 		git = "-"
@@ -202,8 +360,8 @@ func printTableRow(num int, r scanner.RepoResult) {
 		gomod = "-"
 	}
 
-	fmt.Printf("| %d | %s | %s | %s | %s | %s | %s |\n",
-		num, r.Name, uncommitted, replace, mismatch, git, gomod)
+	fmt.Printf("| %d | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+		num, r.Name, uncommitted, replace, goWork, mismatch, errCount, git, gomod)
 }
 
 func printResult(num int, r scanner.RepoResult, maxNameLen int, internalDeps []string) {
@@ -214,9 +372,21 @@ func printResult(num int, r scanner.RepoResult, maxNameLen int, internalDeps []s
 	if r.HasReplaceDirectives {
 		issues = append(issues, fmt.Sprintf("replace:%d", r.ReplaceCount))
 	}
+	if n := len(r.LocalReplaces()); n > 0 {
+		issues = append(issues, fmt.Sprintf("local-replace:%d", n))
+	}
+	if n := len(r.MissingReplaces()); n > 0 {
+		issues = append(issues, fmt.Sprintf("missing-replace:%d", n))
+	}
+	if r.HasCommittedGoWork() {
+		issues = append(issues, "go.work")
+	}
 	if r.HasModuleMismatch {
 		issues = append(issues, "mismatch")
 	}
+	if r.HasErrors() {
+		issues = append(issues, "error")
+	}
 	if !r.IsGitRepo {
 		issues = append(issues, "no-git")
 	}
@@ -234,6 +404,65 @@ func printResult(num int, r scanner.RepoResult, maxNameLen int, internalDeps []s
 	} else {
 		fmt.Printf("%3d. %-*s%s\n", num, maxNameLen, r.Name, depStr)
 	}
+	if r.HasModuleMismatch {
+		fmt.Printf("       module %s, expected %s\n", r.ModuleName, r.ExpectedModulePath)
+	}
+}
+
+// printReplaces prints each replace directive in the repo's go.mod files with
+// its classification. Directives of nested go.mod files are prefixed with the
+// go.mod path.
+func printReplaces(r scanner.RepoResult, indent string) {
+	for _, gm := range r.GoMods() {
+		prefix := ""
+		if gm.Path != "go.mod" {
+			prefix = gm.Path + ": "
+		}
+		for _, rep := range gm.Replaces {
+			old := rep.OldPath
+			if rep.OldVersion != "" {
+				old += " " + rep.OldVersion
+			}
+			target := rep.NewPath
+			if rep.NewVersion != "" {
+				target += " " + rep.NewVersion
+			}
+
+			detail := rep.Kind.String()
+			switch {
+			case rep.IsLocal() && !rep.DirExists:
+				detail += ", missing"
+			case rep.IsLocal() && rep.TargetRepo != "":
+				detail += ", repo: " + rep.TargetRepo
+			}
+			fmt.Printf("%s%sreplace %s => %s [%s]\n", indent, prefix, old, target, detail)
+		}
+	}
+}
+
+// printWorkUses prints each use directive of a go.work file with its module
+// path and the scanned repo it belongs to.
+func printWorkUses(w scanner.GoWorkResult, indent string) {
+	for _, u := range w.Uses {
+		detail := u.ModulePath
+		if detail == "" {
+			detail = "no go.mod"
+		}
+		if u.Repo != "" {
+			detail += ", repo: " + u.Repo
+		}
+		fmt.Printf("%suse %s [%s]\n", indent, u.Path, detail)
+	}
+	if w.HasSum {
+		fmt.Printf("%s(go.work.sum pins %d module versions)\n", indent, len(w.Sums))
+	}
+}
+
+// printErrors prints the errors recorded for a repo, one per line.
+func printErrors(r scanner.RepoResult, indent string) {
+	for _, err := range r.Errors {
+		fmt.Printf("%serror: %v\n", indent, err)
+	}
 }
 
 func joinIssues(issues []string) string {
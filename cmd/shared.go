@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grokify/gitscan/scanner"
+	"github.com/grokify/mogo/fmt/progress"
+	"github.com/spf13/cobra"
 )
 
 // Common flag variables shared across subcommands
@@ -18,12 +23,42 @@ var (
 	useGoGit bool
 )
 
+// Fetch flags shared by scanning commands (see addFetchFlags)
+var (
+	fetchFirst   bool
+	fetchTimeout time.Duration
+)
+
+// statusOut receives scan progress and filter messages. Commands writing
+// structured output to stdout set it to os.Stderr.
+var statusOut io.Writer = os.Stdout
+
 // resolvePath expands ~ and resolves to an absolute path, then validates it exists as a directory.
 func resolvePath(path string) (string, error) {
 	if path == "" {
 		return "", fmt.Errorf("directory path required")
 	}
 
+	absPath, err := expandPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Check if directory exists
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("error accessing directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", absPath)
+	}
+
+	return absPath, nil
+}
+
+// expandPath expands ~ and resolves to an absolute path, which may be a file
+// or not exist yet.
+func expandPath(path string) (string, error) {
 	// Expand ~ to home directory
 	if len(path) > 0 && path[0] == '~' {
 		home, err := os.UserHomeDir()
@@ -38,17 +73,53 @@ func resolvePath(path string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("error resolving path: %w", err)
 	}
+	return absPath, nil
+}
 
-	// Check if directory exists
-	info, err := os.Stat(absPath)
+// scanDirArg returns the directory to scan from the positional argument at
+// index i, falling back to the --dir flag.
+func scanDirArg(args []string, i int, usage string) (string, error) {
+	if len(args) > i {
+		return args[i], nil
+	}
+	if dirPath != "" {
+		return dirPath, nil
+	}
+	return "", fmt.Errorf("directory path required\nUsage: %s", usage)
+}
+
+// scanWithProgress resolves scanDir, scans it with a progress bar, and returns
+// the resolved path and scan results.
+func scanWithProgress(scanDir string, opts scanner.ScanOptions) (string, []scanner.RepoResult, error) {
+	absPath, err := resolvePath(scanDir)
 	if err != nil {
-		return "", fmt.Errorf("error accessing directory: %w", err)
+		return "", nil, err
 	}
-	if !info.IsDir() {
-		return "", fmt.Errorf("%s is not a directory", absPath)
+
+	fmt.Fprintf(statusOut, "Scanning: %s\n", absPath)
+
+	// Count directories first
+	total, err := scanner.CountDirectories(absPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("error counting directories: %w", err)
 	}
+	fmt.Fprintf(statusOut, "Found %d directories to scan\n\n", total)
 
-	return absPath, nil
+	// Progress renderer
+	renderer := progress.NewSingleStageRenderer(statusOut).WithBarWidth(progressBarWidth)
+
+	progressFn := func(current, total int, name string) {
+		renderer.Update(current, total, name)
+	}
+
+	results, err := scanner.ScanDirectoryWithProgress(absPath, progressFn, opts)
+	if err != nil {
+		return "", nil, fmt.Errorf("error scanning directory: %w", err)
+	}
+
+	renderer.Done("Scan complete!")
+
+	return absPath, results, nil
 }
 
 // createGitBackend returns the appropriate git backend based on the useGoGit flag.
@@ -88,3 +159,30 @@ func parseDuration(s string) (time.Duration, error) {
 		return 0, fmt.Errorf("unknown unit: %s", unit)
 	}
 }
+
+// confirm prints prompt and reads a yes/no answer from stdin.
+// Only "y" or "yes" (case-insensitive) confirm; anything else, including EOF, declines.
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// addFetchFlags registers --fetch and --fetch-timeout on cmd.
+func addFetchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&fetchFirst, "fetch", false, "Fetch all remotes of each repo before checking its status")
+	cmd.Flags().DurationVar(&fetchTimeout, "fetch-timeout", scanner.DefaultFetchTimeout, "Time limit for fetching each repo")
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
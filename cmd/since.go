@@ -40,7 +40,7 @@ Examples:
 }
 
 func init() {
-	sinceCmd.Flags().StringVar(&sinceDepFilter, "dep", "", "Also filter by dependency (AND logic)")
+	sinceCmd.Flags().StringVar(&sinceDepFilter, "dep", "", "Also filter by dependency, with an optional version constraint as in dep (AND logic)")
 	sinceCmd.Flags().BoolVarP(&sinceUnpushedOnly, "unpushed", "u", false, "Only show repos with uncommitted changes or unpushed commits")
 	sinceCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
 	sinceCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Check nested go.mod files")
@@ -54,6 +54,12 @@ func runSince(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("invalid duration %q: %v\nValid formats: 7d (days), 2w (weeks), 1m (months), 24h (hours)", sinceStr, err)
 	}
+	var depQuery scanner.DepQuery
+	if sinceDepFilter != "" {
+		if depQuery, err = scanner.ParseDepQuery(sinceDepFilter); err != nil {
+			return err
+		}
+	}
 
 	// Get directory from second argument or flag
 	var scanDir string
@@ -132,7 +138,7 @@ func runSince(cmd *cobra.Command, args []string) error {
 
 		// Check dependency filter (AND logic)
 		if sinceDepFilter != "" {
-			hasDep := result.HasDependency(sinceDepFilter)
+			hasDep := len(result.MatchDependencies(depQuery)) > 0
 			if !hasDep {
 				continue
 			}
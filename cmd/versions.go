@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionsModule   string
+	versionsAll      bool
+	versionsIndirect bool
+)
+
+var versionsCmd = &cobra.Command{
+	Use:   "versions [directory]",
+	Short: "Report dependency version skew across repos",
+	Long: `Report which versions of each required module are used across repositories.
+
+For every required module, lists which repos require which version, highlights
+the newest version, and flags repos lagging behind by major, minor, or patch.
+By default only modules required at more than one version are shown.
+
+Examples:
+  gitscan versions ~/go/src                               # Modules with version skew
+  gitscan versions -m github.com/grokify/mogo ~/go/src    # A single module
+  gitscan versions --all -r ~/go/src                      # All modules, incl. nested go.mod`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVersions,
+}
+
+func init() {
+	versionsCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	versionsCmd.Flags().StringVarP(&versionsModule, "module", "m", "", "Only report this module path")
+	versionsCmd.Flags().BoolVar(&versionsAll, "all", false, "Include modules required at a single version")
+	versionsCmd.Flags().BoolVar(&versionsIndirect, "indirect", false, "Include indirect requirements")
+	versionsCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Check nested go.mod files")
+	versionsCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	rootCmd.AddCommand(versionsCmd)
+}
+
+func runVersions(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan versions [directory]")
+	if err != nil {
+		return err
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		GitBackend: createGitBackend(useGoGit),
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	modules := scanner.CollectModuleVersions(results, versionsIndirect)
+
+	var (
+		shown     int
+		skewCount int
+	)
+	for _, mv := range modules {
+		if versionsModule != "" && mv.Path != versionsModule {
+			continue
+		}
+		if mv.HasSkew() {
+			skewCount++
+		} else if !versionsAll && versionsModule == "" {
+			continue
+		}
+		shown++
+		printModuleVersions(mv)
+	}
+
+	if versionsModule != "" && shown == 0 {
+		fmt.Printf("\nNo repos require %s\n", versionsModule)
+	}
+
+	// Summary
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d repos scanned, %d modules required, %d with version skew\n",
+		len(results), len(modules), skewCount)
+
+	return nil
+}
+
+// printModuleVersions prints each version of a module with the repos requiring it.
+// The newest version is marked, older versions show how far they lag behind.
+func printModuleVersions(mv scanner.ModuleVersions) {
+	versions := mv.Versions()
+	fmt.Printf("\n%s  (%d versions, newest %s)\n", mv.Path, len(versions), mv.Newest)
+
+	maxVersionLen := 0
+	for _, v := range versions {
+		if len(v) > maxVersionLen {
+			maxVersionLen = len(v)
+		}
+	}
+
+	for _, v := range versions {
+		var labels []string
+		for _, u := range mv.Usages {
+			if u.Version != v {
+				continue
+			}
+			label := u.Label()
+			if u.Indirect {
+				label += " (indirect)"
+			}
+			labels = append(labels, label)
+		}
+
+		marker := "newest"
+		if skew := scanner.CompareSkew(v, mv.Newest); skew != scanner.SkewNone {
+			marker = skew.String()
+		}
+		fmt.Printf("  %-*s  %-8s  %s\n", maxVersionLen, v, "["+marker+"]", strings.Join(labels, ", "))
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vulnDBPath string
+	vulnDirect bool
+)
+
+var vulnCmd = &cobra.Command{
+	Use:   "vuln [directory]",
+	Short: "Check required module versions against a local OSV vulnerability database",
+	Long: `Match the required module versions of every repo against an OSV-format
+vulnerability database, without network access.
+
+The database is a directory of OSV .json files (searched recursively) or a zip
+archive, for example the Go vulnerability database or the Go ecosystem export
+of osv.dev:
+
+  curl -O https://osv-vulnerabilities.storage.googleapis.com/Go/all.zip
+
+Each affected requirement is listed with the vulnerability IDs, the version
+fixing it, and whether the dependency is direct or indirect. Requirements
+replaced with another module version are checked at the replacement version;
+requirements replaced with a local directory are not checked. Only module
+versions in go.mod are checked: this does not analyze whether the vulnerable
+code is reachable, and the Go standard library is not checked.
+
+Examples:
+  gitscan vuln --db ~/osv/Go/all.zip ~/go/src/github.com/grokify
+  gitscan vuln --db ~/vulndb --direct -r ~/go/src`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVuln,
+}
+
+func init() {
+	vulnCmd.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+	vulnCmd.Flags().StringVar(&vulnDBPath, "db", "", "OSV database directory or zip file")
+	vulnCmd.Flags().BoolVar(&vulnDirect, "direct", false, "Only check direct dependencies")
+	vulnCmd.Flags().BoolVarP(&recurse, "recurse", "r", false, "Check nested go.mod files")
+	vulnCmd.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	_ = vulnCmd.MarkFlagRequired("db")
+	rootCmd.AddCommand(vulnCmd)
+}
+
+func runVuln(cmd *cobra.Command, args []string) error {
+	scanDir, err := scanDirArg(args, 0, "gitscan vuln --db <path> [directory]")
+	if err != nil {
+		return err
+	}
+	dbPath, err := expandPath(vulnDBPath)
+	if err != nil {
+		return err
+	}
+	db, err := scanner.LoadVulnDB(dbPath)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("error loading vulnerability database: %w", err)
+	}
+	fmt.Fprintf(statusOut, "Loaded %d vulnerabilities affecting Go modules from %s\n", db.Entries, dbPath)
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		GitBackend: createGitBackend(useGoGit),
+	}
+	_, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return err
+	}
+
+	found := scanner.FindVulnerabilities(results, db, !vulnDirect)
+	if len(found) == 0 {
+		fmt.Println("\nNo vulnerable requirements found")
+	} else {
+		fmt.Println("\nVulnerable requirements:")
+		fmt.Println("------------------------")
+	}
+
+	var (
+		shown      int
+		lastLabel  string
+		repos      = make(map[string]bool)
+		vulnIDs    = make(map[string]bool)
+		directReqs int
+	)
+	for _, v := range found {
+		repos[v.Repo] = true
+		if !v.Indirect {
+			directReqs++
+		}
+		if v.Label() != lastLabel {
+			shown++
+			lastLabel = v.Label()
+			fmt.Printf("%3d. %s\n", shown, lastLabel)
+		}
+		kind := "direct"
+		if v.Indirect {
+			kind = "indirect"
+		}
+		if v.Replacement != "" {
+			fmt.Printf("       %s %s => %s (%s)\n", v.Module, v.Version, v.Replacement, kind)
+		} else {
+			fmt.Printf("       %s %s (%s)\n", v.Module, v.Version, kind)
+		}
+
+		maxIDLen := 0
+		for _, vuln := range v.Vulns {
+			maxIDLen = max(maxIDLen, len(vuln.ID))
+		}
+		for _, vuln := range v.Vulns {
+			vulnIDs[vuln.ID] = true
+			fixed := "no fix"
+			if vuln.Fixed != "" {
+				fixed = "fixed in " + vuln.Fixed
+			}
+			line := fmt.Sprintf("         %-*s  %-16s  %s", maxIDLen, vuln.ID, fixed, vulnSummary(vuln))
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Summary: %d repos scanned, %d affected, %d vulnerable requirements (%d direct), %d vulnerabilities\n",
+		len(results), len(repos), len(found), directReqs, len(vulnIDs))
+
+	return nil
+}
+
+// vulnSummary returns the vulnerability summary followed by its aliases
+// (e.g., CVE IDs).
+func vulnSummary(v scanner.Vuln) string {
+	if len(v.Aliases) == 0 {
+		return v.Summary
+	}
+	aliases := "(" + strings.Join(v.Aliases, ", ") + ")"
+	if v.Summary == "" {
+		return aliases
+	}
+	return v.Summary + " " + aliases
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/grokify/gitscan/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workFilter    repoFilter
+	workOutput    string
+	workGoVersion string
+	workForce     bool
+	workDryRun    bool
+)
+
+var workCmd = &cobra.Command{
+	Use:   "work",
+	Short: "Generate and update go.work files for selected repos",
+	Long: `Generate and update go.work files that use the repos selected by the
+--since, --dep, --transitive, --unpushed and seed filters (the same filters as
+order).
+
+The go.work file is written to the scanned directory unless --output is set.`,
+}
+
+var workInitCmd = &cobra.Command{
+	Use:   "init [directory]",
+	Short: "Create a go.work using the selected repos",
+	Long: `Create a go.work file that uses the modules of the selected repos.
+
+The go directive defaults to the highest go version among the selected modules.
+
+Examples:
+  gitscan work init -s 7d -t ~/go/src                  # Repos changed this week and their dependents
+  gitscan work init --dep github.com/foo/bar ~/go/src  # Repos depending on a module
+  gitscan work init -s 7d -o ~/scratch ~/go/src        # Write go.work to another directory`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkInit,
+}
+
+var workSyncCmd = &cobra.Command{
+	Use:   "sync [directory]",
+	Short: "Update an existing go.work to match the selected repos",
+	Long: `Update an existing go.work file to match the current selection.
+
+Modules of newly selected repos are added, and uses pointing into scanned repos
+that are no longer selected are removed. Uses outside the scanned repos, the go
+directive, replace directives, and comments are left unchanged.
+
+Examples:
+  gitscan work sync -s 7d -t ~/go/src
+  gitscan work sync -s 7d -t -n ~/go/src   # Show the diff only`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkSync,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{workInitCmd, workSyncCmd} {
+		c.Flags().StringVarP(&dirPath, "dir", "d", "", "Directory to scan")
+		workFilter.addFlags(c)
+		c.Flags().StringVarP(&workOutput, "output", "o", "", "Directory containing the go.work file (default: scanned directory)")
+		c.Flags().BoolVarP(&workDryRun, "dry-run", "n", false, "Show the go.work contents or diff without writing")
+		c.Flags().BoolVarP(&recurse, "recurse", "r", false, "Include nested go.mod modules")
+		c.Flags().BoolVar(&useGoGit, "go-git", false, "Use go-git library instead of git CLI")
+	}
+	workInitCmd.Flags().StringVar(&workGoVersion, "go", "", "Go version for the go directive (default: highest among selected modules)")
+	workInitCmd.Flags().BoolVar(&workForce, "force", false, "Overwrite an existing go.work")
+	workCmd.AddCommand(workInitCmd, workSyncCmd)
+	rootCmd.AddCommand(workCmd)
+}
+
+func runWorkInit(cmd *cobra.Command, args []string) error {
+	absPath, results, selected, err := scanWorkSelection(args, "gitscan work init [directory]")
+	if err != nil {
+		return err
+	}
+
+	goWorkPath, err := workFilePath(absPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(goWorkPath); err == nil && !workForce && !workDryRun {
+		return fmt.Errorf("%s already exists; use --force to overwrite or `gitscan work sync` to update it", goWorkPath)
+	}
+
+	var (
+		dirs   []string
+		goMods []scanner.GoModResult
+	)
+	for _, r := range selected {
+		dirs = append(dirs, r.ModuleDirs()...)
+		goMods = append(goMods, r.GoMods()...)
+	}
+
+	goVersion := workGoVersion
+	if goVersion == "" {
+		goVersion = scanner.MaxGoVersion(goMods)
+	}
+
+	data, err := scanner.NewGoWork(filepath.Dir(goWorkPath), goVersion, dirs)
+	if err != nil {
+		return fmt.Errorf("error generating go.work: %w", err)
+	}
+
+	fmt.Println()
+	if workDryRun {
+		fmt.Printf("%s (dry run):\n\n%s", goWorkPath, data)
+		return nil
+	}
+	if err := os.WriteFile(goWorkPath, data, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Wrote %s using %d modules from %d of %d repos\n", goWorkPath, len(dirs), len(selected), len(results))
+	return nil
+}
+
+func runWorkSync(cmd *cobra.Command, args []string) error {
+	absPath, results, selected, err := scanWorkSelection(args, "gitscan work sync [directory]")
+	if err != nil {
+		return err
+	}
+
+	goWorkPath, err := workFilePath(absPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(goWorkPath); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s does not exist; use `gitscan work init` to create it", goWorkPath)
+	}
+
+	var dirs []string
+	for _, r := range selected {
+		dirs = append(dirs, r.ModuleDirs()...)
+	}
+	isManaged := func(dir string) bool {
+		return scanner.FindRepoForDir(dir, results) != ""
+	}
+
+	original, updated, added, removed, err := scanner.SyncGoWork(goWorkPath, dirs, isManaged)
+	if err != nil {
+		return fmt.Errorf("error updating go.work: %w", err)
+	}
+
+	fmt.Println()
+	for _, usePath := range added {
+		fmt.Printf("  + use %s\n", usePath)
+	}
+	for _, usePath := range removed {
+		fmt.Printf("  - use %s\n", usePath)
+	}
+
+	if workDryRun && (len(added) > 0 || len(removed) > 0) {
+		fmt.Println()
+		fmt.Print(unifiedDiff("go.work", original, updated))
+	}
+
+	fmt.Println()
+	fmt.Println("----------------------------------------")
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Printf("%s is up to date\n", goWorkPath)
+		return nil
+	}
+	if workDryRun {
+		fmt.Printf("Dry run: %d uses would be added, %d removed\n", len(added), len(removed))
+		return nil
+	}
+	if err := os.WriteFile(goWorkPath, updated, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s: %d uses added, %d removed\n", goWorkPath, len(added), len(removed))
+	return nil
+}
+
+// scanWorkSelection scans the directory and applies the work filters,
+// returning the scanned path, all results, and the selected repos with a
+// go.mod sorted by name.
+func scanWorkSelection(args []string, usage string) (string, []scanner.RepoResult, []scanner.RepoResult, error) {
+	scanDir, err := scanDirArg(args, 0, usage)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if err := workFilter.validate(); err != nil {
+		return "", nil, nil, err
+	}
+
+	opts := scanner.ScanOptions{
+		Recurse:    recurse,
+		GitBackend: createGitBackend(useGoGit),
+	}
+	workFilter.scanOptions(&opts)
+	absPath, results, err := scanWithProgress(scanDir, opts)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	filtered, err := workFilter.selectRepos(results)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var selected []scanner.RepoResult
+	for _, r := range workFilter.filterUnpushed(filtered) {
+		if r.HasGoMod || len(r.GoModFiles) > 0 {
+			selected = append(selected, r)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	return absPath, results, selected, nil
+}
+
+// workFilePath returns the go.work path in the --output directory,
+// defaulting to the scanned directory.
+func workFilePath(absPath string) (string, error) {
+	outDir := absPath
+	if workOutput != "" {
+		var err error
+		outDir, err = resolvePath(workOutput)
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(outDir, "go.work"), nil
+}
@@ -6,6 +6,7 @@ require (
 	github.com/go-git/go-git/v5 v5.17.2
 	github.com/grokify/mogo v0.74.0
 	github.com/spf13/cobra v1.10.2
+	golang.org/x/mod v0.35.0
 )
 
 require (
@@ -0,0 +1,274 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Dependency is a requirement of a managed module by another repo: an edge
+// in the dependency graph.
+type Dependency struct {
+	From      string // Dependent repo
+	To        string // Repo owning the required module
+	GoModPath string // Path to the go.mod containing the requirement, relative to From's root
+	Module    string // Required module path
+	Version   string // Required version
+	Line      int    // Line of the requirement in go.mod
+}
+
+// String returns the requirement with its location,
+// e.g. "goauth/go.mod:6: github.com/grokify/mogo v0.70.0".
+func (d Dependency) String() string {
+	loc := path.Join(d.From, d.GoModPath)
+	if d.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", loc, d.Line)
+	}
+	return fmt.Sprintf("%s: %s %s", loc, d.Module, d.Version)
+}
+
+// Cycle is a circular dependency between repos (or release units).
+type Cycle struct {
+	Path  []string     // Nodes in the cycle, starting with the lowest name; the last node depends on Path[0]
+	Edges []Dependency // Requirement responsible for each step, starting with Path[0] -> Path[1]
+}
+
+// String returns the cycle as a path, e.g. "a -> b -> c -> a".
+func (c Cycle) String() string {
+	if len(c.Path) == 0 {
+		return ""
+	}
+	return strings.Join(append(slices.Clone(c.Path), c.Path[0]), " -> ")
+}
+
+// condensation is a dependency graph with each strongly connected component
+// (cycle) contracted into a single node labeled with its lowest member.
+type condensation struct {
+	labels  []string            // Component labels
+	members map[string][]string // Label -> component members, sorted
+	deps    map[string][]string // Label -> labels of components it depends on
+	cycles  [][]string          // Components with more than one member, sorted by label
+}
+
+// condense contracts the strongly connected components of the graph.
+// deps maps a node to its dependencies; dependencies that are not in nodes
+// are ignored.
+func condense(nodes []string, deps map[string][]string) condensation {
+	c := condensation{
+		members: make(map[string][]string),
+		deps:    make(map[string][]string),
+	}
+
+	labelOf := make(map[string]string)
+	for _, comp := range stronglyConnected(nodes, deps) {
+		for _, n := range comp {
+			labelOf[n] = comp[0]
+		}
+		c.members[comp[0]] = comp
+		c.labels = append(c.labels, comp[0])
+		if len(comp) > 1 {
+			c.cycles = append(c.cycles, comp)
+		}
+	}
+
+	for _, n := range nodes {
+		for _, dep := range deps[n] {
+			depLabel, ok := labelOf[dep]
+			if !ok || depLabel == labelOf[n] || slices.Contains(c.deps[labelOf[n]], depLabel) {
+				continue
+			}
+			c.deps[labelOf[n]] = append(c.deps[labelOf[n]], depLabel)
+		}
+	}
+
+	slices.SortFunc(c.cycles, func(a, b []string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	return c
+}
+
+// orderNodes orders nodes so each node comes after the nodes it depends on.
+// Strongly connected components (cycles) are contracted and their members
+// listed together in name order, so nodes downstream of a cycle are still
+// ordered after it. Ties are broken alphabetically. Returns the order and the
+// components with more than one node.
+func orderNodes(nodes []string, deps map[string][]string) (sorted []string, cycles [][]string) {
+	c := condense(nodes, deps)
+
+	// The condensed graph is acyclic, so every component is ordered
+	order, _ := kahnSort(c.labels, c.deps)
+	for _, label := range order {
+		sorted = append(sorted, c.members[label]...)
+	}
+	return sorted, c.cycles
+}
+
+// levelNodes groups nodes into levels where each node only depends on nodes
+// in earlier levels: a node's level is one more than the highest level of its
+// dependencies. Members of a cycle share a level. Nodes within a level are
+// sorted. Returns the levels and the components with more than one node.
+func levelNodes(nodes []string, deps map[string][]string) (levels [][]string, cycles [][]string) {
+	c := condense(nodes, deps)
+
+	order, _ := kahnSort(c.labels, c.deps)
+	levelOf := make(map[string]int)
+	for _, label := range order {
+		level := 0
+		for _, dep := range c.deps[label] {
+			level = max(level, levelOf[dep]+1)
+		}
+		levelOf[label] = level
+		if level == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[level] = append(levels[level], c.members[label]...)
+	}
+
+	for _, level := range levels {
+		slices.Sort(level)
+	}
+	return levels, c.cycles
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// using Tarjan's algorithm. deps maps a node to its dependencies; dependencies
+// that are not in nodes are ignored. Members of each component are sorted.
+func stronglyConnected(nodes []string, deps map[string][]string) [][]string {
+	inGraph := make(map[string]bool)
+	for _, n := range nodes {
+		inGraph[n] = true
+	}
+
+	var (
+		index      = make(map[string]int)
+		lowlink    = make(map[string]int)
+		onStack    = make(map[string]bool)
+		stack      []string
+		components [][]string
+		visit      func(n string)
+	)
+	visit = func(n string) {
+		index[n] = len(index)
+		lowlink[n] = index[n]
+		stack = append(stack, n)
+		onStack[n] = true
+
+		for _, dep := range deps[n] {
+			if !inGraph[dep] {
+				continue
+			}
+			if _, seen := index[dep]; !seen {
+				visit(dep)
+				lowlink[n] = min(lowlink[n], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[n] = min(lowlink[n], index[dep])
+			}
+		}
+
+		// n is the root of a component: pop its members
+		if lowlink[n] == index[n] {
+			var comp []string
+			for {
+				m := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[m] = false
+				comp = append(comp, m)
+				if m == n {
+					break
+				}
+			}
+			slices.Sort(comp)
+			components = append(components, comp)
+		}
+	}
+
+	for _, n := range slices.Sorted(slices.Values(nodes)) {
+		if _, seen := index[n]; !seen {
+			visit(n)
+		}
+	}
+	return components
+}
+
+// graphDeps returns the dependency lists of graph, sorted by name.
+func graphDeps(graph map[string]map[string]Dependency) map[string][]string {
+	deps := make(map[string][]string)
+	for n, edges := range graph {
+		for dep := range edges {
+			deps[n] = append(deps[n], dep)
+		}
+		slices.Sort(deps[n])
+	}
+	return deps
+}
+
+// findCycles returns explicit cycles covering every member of the given
+// strongly connected components. For each member not yet on a reported cycle,
+// the shortest cycle through it is added, so a component with several
+// overlapping cycles may produce more than one. graph maps a node to its
+// dependencies and the requirement responsible for each edge.
+func findCycles(components [][]string, graph map[string]map[string]Dependency) []Cycle {
+	var cycles []Cycle
+	for _, comp := range components {
+		covered := make(map[string]bool)
+		for _, start := range comp {
+			if covered[start] {
+				continue
+			}
+			cyclePath := shortestCycle(start, comp, graph)
+			if len(cyclePath) == 0 {
+				continue
+			}
+			// Rotate so the cycle starts with its lowest node
+			lowest := slices.Index(cyclePath, slices.Min(cyclePath))
+			cyclePath = slices.Concat(cyclePath[lowest:], cyclePath[:lowest])
+
+			cycle := Cycle{Path: cyclePath}
+			for i, n := range cyclePath {
+				next := cyclePath[(i+1)%len(cyclePath)]
+				cycle.Edges = append(cycle.Edges, graph[n][next])
+				covered[n] = true
+			}
+			cycles = append(cycles, cycle)
+		}
+	}
+	return cycles
+}
+
+// shortestCycle returns the shortest path from start back to itself using
+// only nodes in comp, or nil if there is none. The returned path starts with
+// start and does not repeat it at the end.
+func shortestCycle(start string, comp []string, graph map[string]map[string]Dependency) []string {
+	parent := make(map[string]string)
+	queue := []string{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		deps := make([]string, 0, len(graph[n]))
+		for dep := range graph[n] {
+			deps = append(deps, dep)
+		}
+		slices.Sort(deps)
+
+		for _, dep := range deps {
+			if !slices.Contains(comp, dep) {
+				continue
+			}
+			if dep == start {
+				// Walk back from n to start
+				cyclePath := []string{n}
+				for cyclePath[0] != start {
+					cyclePath = append([]string{parent[cyclePath[0]]}, cyclePath...)
+				}
+				return cyclePath
+			}
+			if _, seen := parent[dep]; !seen {
+				parent[dep] = n
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package scanner
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testGraph builds a dependency graph from "from->to" edges. Every node named
+// in an edge or in extra is part of the returned node list.
+func testGraph(edges []string, extra ...string) ([]string, map[string]map[string]Dependency) {
+	nodes := slices.Clone(extra)
+	graph := make(map[string]map[string]Dependency)
+	addNode := func(n string) {
+		if !slices.Contains(nodes, n) {
+			nodes = append(nodes, n)
+		}
+		if graph[n] == nil {
+			graph[n] = make(map[string]Dependency)
+		}
+	}
+	for _, e := range edges {
+		from, to, _ := strings.Cut(e, "->")
+		addNode(from)
+		addNode(to)
+		graph[from][to] = Dependency{From: from, To: to, GoModPath: "go.mod", Module: "example.com/" + to, Version: "v1.0.0"}
+	}
+	for _, n := range extra {
+		addNode(n)
+	}
+	slices.Sort(nodes)
+	return nodes, graph
+}
+
+func TestStronglyConnected(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges []string
+		want  []string // Components with members joined by ",", sorted
+	}{
+		{"acyclic", []string{"b->a", "c->b"}, []string{"a", "b", "c"}},
+		{"self-loop", []string{"a->a", "b->a"}, []string{"a", "b"}},
+		{"two-node cycle", []string{"a->b", "b->a"}, []string{"a,b"}},
+		{"downstream of cycle", []string{"a->b", "b->a", "c->a"}, []string{"a,b", "c"}},
+		{"two independent cycles", []string{"a->b", "b->a", "x->y", "y->x"}, []string{"a,b", "x,y"}},
+		{"three-node cycle", []string{"a->b", "b->c", "c->a", "d->c"}, []string{"a,b,c", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, graph := testGraph(tt.edges)
+			var got []string
+			for _, comp := range stronglyConnected(nodes, graphDeps(graph)) {
+				got = append(got, strings.Join(comp, ","))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("stronglyConnected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKahnSort(t *testing.T) {
+	tests := []struct {
+		name          string
+		edges         []string
+		nodes         []string // Nodes to sort (default: all nodes in edges)
+		wantSorted    []string
+		wantRemaining []string
+	}{
+		{"acyclic", []string{"c->b", "b->a", "d->a"}, nil, []string{"a", "b", "d", "c"}, nil},
+		{"dependency outside nodes ignored", []string{"b->a"}, []string{"b"}, []string{"b"}, nil},
+		{"self-loop", []string{"a->a", "b->a"}, nil, nil, []string{"a", "b"}},
+		{"cycle with downstream node", []string{"a->b", "b->a", "c->a", "d->e"}, nil, []string{"e", "d"}, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, graph := testGraph(tt.edges)
+			if tt.nodes != nil {
+				nodes = tt.nodes
+			}
+			sorted, remaining := kahnSort(nodes, graphDeps(graph))
+			if !slices.Equal(sorted, tt.wantSorted) {
+				t.Errorf("kahnSort() sorted = %v, want %v", sorted, tt.wantSorted)
+			}
+			if !slices.Equal(remaining, tt.wantRemaining) {
+				t.Errorf("kahnSort() remaining = %v, want %v", remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestOrderNodes(t *testing.T) {
+	nodes, graph := testGraph([]string{"b->a", "a->b", "c->a", "d->d", "y->x", "x->y", "z->c"}, "e")
+	sorted, cycles := orderNodes(nodes, graphDeps(graph))
+
+	want := []string{"a", "b", "d", "e", "x", "y", "c", "z"}
+	if !slices.Equal(sorted, want) {
+		t.Errorf("orderNodes() sorted = %v, want %v", sorted, want)
+	}
+	wantCycles := [][]string{{"a", "b"}, {"x", "y"}}
+	if !slices.EqualFunc(cycles, wantCycles, slices.Equal) {
+		t.Errorf("orderNodes() cycles = %v, want %v", cycles, wantCycles)
+	}
+}
+
+func TestLevelNodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		edges      []string
+		wantLevels []string // Members of each level joined by ","
+	}{
+		{"chain", []string{"b->a", "c->b"}, []string{"a", "b", "c"}},
+		{"diamond", []string{"b->a", "c->a", "d->b", "d->c"}, []string{"a", "b,c", "d"}},
+		{"critical path", []string{"c->b", "b->a", "d->a"}, []string{"a", "b,d", "c"}},
+		{"cycle shares a level", []string{"a->b", "b->a", "c->a", "b->z"}, []string{"z", "a,b", "c"}},
+		{"self-loop", []string{"a->a", "b->a"}, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, graph := testGraph(tt.edges)
+			levels, _ := levelNodes(nodes, graphDeps(graph))
+			var got []string
+			for _, level := range levels {
+				got = append(got, strings.Join(level, ","))
+			}
+			if !slices.Equal(got, tt.wantLevels) {
+				t.Errorf("levelNodes() = %v, want %v", got, tt.wantLevels)
+			}
+		})
+	}
+}
+
+func TestFindCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges []string
+		want  []string // Cycle strings in order
+	}{
+		{"acyclic", []string{"b->a", "c->b"}, nil},
+		{"self-loop is not a cycle", []string{"a->a"}, nil},
+		{"two-node cycle", []string{"b->a", "a->b"}, []string{"a -> b -> a"}},
+		{"downstream node not reported", []string{"a->b", "b->a", "c->a", "d->c"}, []string{"a -> b -> a"}},
+		{"two independent cycles", []string{"x->y", "y->x", "a->b", "b->c", "c->a"}, []string{"a -> b -> c -> a", "x -> y -> x"}},
+		{"overlapping cycles cover all members", []string{"a->b", "b->a", "b->c", "c->a"}, []string{"a -> b -> a", "a -> b -> c -> a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, graph := testGraph(tt.edges)
+			_, components := orderNodes(nodes, graphDeps(graph))
+
+			var got []string
+			for _, c := range findCycles(components, graph) {
+				got = append(got, c.String())
+				if len(c.Edges) != len(c.Path) {
+					t.Fatalf("cycle %s has %d edges, want %d", c, len(c.Edges), len(c.Path))
+				}
+				for i, e := range c.Edges {
+					if e.From != c.Path[i] || e.To != c.Path[(i+1)%len(c.Path)] {
+						t.Errorf("cycle %s edge %d = %s -> %s", c, i, e.From, e.To)
+					}
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("findCycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	repo := func(name string, deps ...string) RepoResult {
+		gm := &GoModResult{Path: "go.mod", ModuleName: "example.com/" + name}
+		for i, d := range deps {
+			gm.Requires = append(gm.Requires, Require{Path: "example.com/" + d, Version: "v1.0.0", Line: 5 + i})
+			gm.Dependencies = append(gm.Dependencies, "example.com/"+d)
+		}
+		return RepoResult{Name: name, ModuleName: gm.ModuleName, GoMod: gm, Dependencies: gm.Dependencies}
+	}
+	results := []RepoResult{repo("app", "lib"), repo("lib", "util"), repo("util", "lib"), repo("base")}
+
+	sorted, cycles := TopologicalSort(results)
+	var names []string
+	for _, r := range sorted {
+		names = append(names, r.Name)
+	}
+	if want := []string{"base", "lib", "util", "app"}; !slices.Equal(names, want) {
+		t.Errorf("TopologicalSort() = %v, want %v", names, want)
+	}
+	if len(cycles) != 1 || cycles[0].String() != "lib -> util -> lib" {
+		t.Fatalf("TopologicalSort() cycles = %v, want [lib -> util -> lib]", cycles)
+	}
+	if got, want := cycles[0].Edges[0].String(), "lib/go.mod:5: example.com/util v1.0.0"; got != want {
+		t.Errorf("cycle edge = %q, want %q", got, want)
+	}
+}
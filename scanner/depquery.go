@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// DepQuery matches required modules by path and, optionally, by version.
+//
+// The path is a module path or a pattern where "..." matches any string, as
+// in go package patterns: "golang.org/x/..." matches golang.org/x and every
+// module below it. The optional version constraint follows an "@" and is a
+// comma-separated list of conditions that must all hold:
+//
+//	v1.4.0               exactly v1.4.0
+//	<v1.4.0, >=v1.2.0    comparisons (<, <=, >, >=, =, !=)
+//	v1.2.x, v1.2         any v1.2 version
+//	v1.x, v1             any v1 version
+type DepQuery struct {
+	Path       string // Module path or pattern
+	Constraint string // Version constraint ("" for any version)
+
+	pathRE     *regexp.Regexp // Set for patterns containing "..."
+	conditions []versionCondition
+}
+
+// versionCondition is a single comparison against a version. An op of "~"
+// matches versions with the same major (and minor) version as prefix.
+type versionCondition struct {
+	op      string
+	version string
+}
+
+// ParseDepQuery parses a dependency query of the form path[@constraint],
+// e.g. "github.com/grokify/mogo@<v0.72.0" or "golang.org/x/...".
+func ParseDepQuery(s string) (DepQuery, error) {
+	path, constraint, _ := strings.Cut(s, "@")
+	q := DepQuery{Path: path, Constraint: constraint}
+	if path == "" {
+		return q, fmt.Errorf("invalid dependency %q: module path required", s)
+	}
+	if strings.Contains(path, "...") {
+		re := regexp.QuoteMeta(path)
+		if strings.HasSuffix(re, `/\.\.\.`) {
+			re = strings.TrimSuffix(re, `/\.\.\.`) + `(/\.\.\.)?`
+		}
+		q.pathRE = regexp.MustCompile("^" + strings.ReplaceAll(re, `\.\.\.`, `.*`) + "$")
+	}
+
+	if constraint == "" {
+		if strings.HasSuffix(s, "@") {
+			return q, fmt.Errorf("invalid dependency %q: version constraint required after @", s)
+		}
+		return q, nil
+	}
+	for _, part := range strings.Split(constraint, ",") {
+		c, err := parseVersionCondition(strings.TrimSpace(part))
+		if err != nil {
+			return q, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+		q.conditions = append(q.conditions, c)
+	}
+	return q, nil
+}
+
+// parseVersionCondition parses a single condition such as "<v1.4.0" or
+// "v1.2.x". The "v" prefix of the version is optional.
+func parseVersionCondition(s string) (versionCondition, error) {
+	var c versionCondition
+	for _, op := range []string{"<=", ">=", "!=", "<", ">", "="} {
+		if strings.HasPrefix(s, op) {
+			c.op = op
+			s = strings.TrimSpace(strings.TrimPrefix(s, op))
+			break
+		}
+	}
+	raw := s
+	if s != "" && !strings.HasPrefix(s, "v") {
+		s = "v" + s
+	}
+
+	if prefix, ok := strings.CutSuffix(s, ".x"); ok {
+		if c.op != "" {
+			return c, fmt.Errorf("%s%s: wildcard versions cannot be compared", c.op, s)
+		}
+		s = prefix
+		if semver.Canonical(s) == "" || strings.Count(s, ".") > 1 {
+			return c, fmt.Errorf("%s.x: expected vMAJOR.x or vMAJOR.MINOR.x", s)
+		}
+	}
+	if !semver.IsValid(s) {
+		return c, fmt.Errorf("%q is not a semantic version", raw)
+	}
+	if c.op == "" {
+		c.op = "="
+		if strings.Count(semver.Canonical(s), ".") != strings.Count(s, ".") {
+			c.op = "~" // v1 or v1.2: any version with that prefix
+		}
+	}
+	c.version = s
+	return c, nil
+}
+
+// String returns the query as written, e.g. "golang.org/x/...@<v0.23.0".
+func (q DepQuery) String() string {
+	if q.Constraint == "" {
+		return q.Path
+	}
+	return q.Path + "@" + q.Constraint
+}
+
+// MatchesPath returns true if modulePath matches the query path or pattern.
+func (q DepQuery) MatchesPath(modulePath string) bool {
+	if q.pathRE != nil {
+		return q.pathRE.MatchString(modulePath)
+	}
+	return modulePath == q.Path
+}
+
+// MatchesVersion returns true if version satisfies every condition of the
+// version constraint. Any version matches a query without a constraint.
+func (q DepQuery) MatchesVersion(version string) bool {
+	for _, c := range q.conditions {
+		if !c.matches(version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c versionCondition) matches(version string) bool {
+	if c.op == "~" {
+		if strings.Count(c.version, ".") == 0 {
+			return semver.Major(version) == c.version
+		}
+		return semver.MajorMinor(version) == c.version
+	}
+	cmp := semver.Compare(version, c.version)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "!=":
+		return cmp != 0
+	}
+	return cmp == 0
+}
+
+// DepMatch is a go.mod requirement matched by a DepQuery.
+type DepMatch struct {
+	ModuleUsage
+	Module string // Required module path
+}
+
+// MatchDependencies returns the requirements of the repo's go.mod files (root
+// and nested) matching the query, in go.mod order.
+func (r RepoResult) MatchDependencies(q DepQuery) []DepMatch {
+	var matches []DepMatch
+	for _, gm := range r.GoMods() {
+		for _, req := range gm.Requires {
+			if !q.MatchesPath(req.Path) || !q.MatchesVersion(req.Version) {
+				continue
+			}
+			matches = append(matches, DepMatch{
+				ModuleUsage: ModuleUsage{
+					Repo:      r.Name,
+					GoModPath: gm.Path,
+					Version:   req.Version,
+					Indirect:  req.Indirect,
+				},
+				Module: req.Path,
+			})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,150 @@
+package scanner
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDepQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		match   []string // "path@version" matched by the query
+		noMatch []string // "path@version" not matched by the query
+	}{
+		{
+			"github.com/grokify/mogo",
+			[]string{"github.com/grokify/mogo@v0.1.0", "github.com/grokify/mogo@v2.0.0"},
+			[]string{"github.com/grokify/mogo/v2@v2.0.0", "github.com/grokify/mogox@v0.1.0"},
+		},
+		{
+			"golang.org/x/...",
+			[]string{"golang.org/x@v0.1.0", "golang.org/x/net@v0.20.0", "golang.org/x/crypto/ssh@v0.1.0"},
+			[]string{"golang.org/xyz@v0.1.0", "golang.org/xyz/net@v0.1.0", "example.com/golang.org/x/net@v0.1.0"},
+		},
+		{
+			"github.com/.../mogo",
+			[]string{"github.com/grokify/mogo@v1.0.0", "github.com/a/b/mogo@v1.0.0"},
+			[]string{"github.com/grokify/mogo/v2@v2.0.0", "gitlab.com/grokify/mogo@v1.0.0"},
+		},
+		{
+			"example.com/mod@v1.2.3",
+			[]string{"example.com/mod@v1.2.3"},
+			[]string{"example.com/mod@v1.2.4", "example.com/mod@v1.2.3-rc.1"},
+		},
+		{
+			"example.com/mod@1.2.3",
+			[]string{"example.com/mod@v1.2.3"},
+			[]string{"example.com/mod@v1.2.4"},
+		},
+		{
+			"example.com/mod@v1.2.x",
+			[]string{"example.com/mod@v1.2.0", "example.com/mod@v1.2.9", "example.com/mod@v1.2.0-rc.1"},
+			[]string{"example.com/mod@v1.3.0", "example.com/mod@v1.1.9", "example.com/mod@v2.2.0"},
+		},
+		{
+			"example.com/mod@v1.2",
+			[]string{"example.com/mod@v1.2.0", "example.com/mod@v1.2.9"},
+			[]string{"example.com/mod@v1.3.0", "example.com/mod@v1.20.0"},
+		},
+		{
+			"example.com/mod@v1",
+			[]string{"example.com/mod@v1.0.0", "example.com/mod@v1.9.3"},
+			[]string{"example.com/mod@v0.9.0", "example.com/mod@v2.0.0", "example.com/mod@v10.0.0"},
+		},
+		{
+			"example.com/mod@v1.x",
+			[]string{"example.com/mod@v1.0.0", "example.com/mod@v1.9.3"},
+			[]string{"example.com/mod@v2.0.0"},
+		},
+		{
+			"example.com/mod@>=v1.2.0,<v1.4.0",
+			[]string{"example.com/mod@v1.2.0", "example.com/mod@v1.3.9"},
+			[]string{"example.com/mod@v1.1.9", "example.com/mod@v1.4.0", "example.com/mod@v1.2.0-rc.1"},
+		},
+		{
+			"example.com/mod@> v1.2.0, != v1.3.0, <= v1.4.0",
+			[]string{"example.com/mod@v1.2.1", "example.com/mod@v1.4.0"},
+			[]string{"example.com/mod@v1.2.0", "example.com/mod@v1.3.0", "example.com/mod@v1.4.1"},
+		},
+		{
+			"golang.org/x/...@<v0.23.0",
+			[]string{"golang.org/x/net@v0.22.0", "golang.org/x/net@v0.0.0-20240101000000-abcdefabcdef"},
+			[]string{"golang.org/x/net@v0.23.0", "golang.org/xyz@v0.1.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseDepQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.String() != tt.query {
+				t.Errorf("String() = %q, want %q", q.String(), tt.query)
+			}
+			matches := func(s string) bool {
+				path, version, _ := strings.Cut(s, "@")
+				return q.MatchesPath(path) && q.MatchesVersion(version)
+			}
+			for _, s := range tt.match {
+				if !matches(s) {
+					t.Errorf("%s does not match", s)
+				}
+			}
+			for _, s := range tt.noMatch {
+				if matches(s) {
+					t.Errorf("%s matches", s)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDepQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // Substring of the error
+	}{
+		{"", "module path required"},
+		{"@v1.0.0", "module path required"},
+		{"example.com/mod@", "version constraint required after @"},
+		{"example.com/mod@<=v1.2.x", "wildcard versions cannot be compared"},
+		{"example.com/mod@>v1.x", "wildcard versions cannot be compared"},
+		{"example.com/mod@v1.2.3.x", "expected vMAJOR.x or vMAJOR.MINOR.x"},
+		{"example.com/mod@junk", `"junk" is not a semantic version`},
+		{"example.com/mod@>=v1.2.0,", "is not a semantic version"},
+		{"example.com/mod@<", "is not a semantic version"},
+	}
+	for _, tt := range tests {
+		_, err := ParseDepQuery(tt.query)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseDepQuery(%q) error = %v, want %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestMatchDependencies(t *testing.T) {
+	r := RepoResult{
+		Name: "app",
+		GoMod: &GoModResult{Path: "go.mod", Requires: []Require{
+			{Path: "golang.org/x/net", Version: "v0.20.0"},
+			{Path: "golang.org/xyz", Version: "v0.1.0"},
+			{Path: "golang.org/x/text", Version: "v0.14.0", Indirect: true},
+		}},
+		GoModFiles: []GoModResult{{Path: "tools/go.mod", Requires: []Require{
+			{Path: "golang.org/x/net", Version: "v0.25.0"},
+		}}},
+	}
+	q, err := ParseDepQuery("golang.org/x/...@<v0.23.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range r.MatchDependencies(q) {
+		got = append(got, m.Label()+": "+m.Module+" "+m.Version)
+	}
+	want := []string{"app: golang.org/x/net v0.20.0", "app: golang.org/x/text v0.14.0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("MatchDependencies() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,267 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// GoModEdit is a planned rewrite of a single go.mod file.
+type GoModEdit struct {
+	Repo      string   // Repository name
+	GoModPath string   // Path to go.mod relative to the repo root
+	File      string   // Path to go.mod on disk
+	Original  []byte   // File contents before the edit
+	Updated   []byte   // File contents after the edit
+	Changes   []string // Human-readable description of each change
+	Skipped   []string // Changes that were not made, with the reason
+}
+
+// Changed returns true if the edit modifies the file.
+func (e GoModEdit) Changed() bool {
+	return !bytes.Equal(e.Original, e.Updated)
+}
+
+// Apply writes the updated contents to the go.mod file.
+func (e GoModEdit) Apply() error {
+	if !e.Changed() {
+		return nil
+	}
+	info, err := os.Stat(e.File)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(e.File, e.Updated, info.Mode().Perm())
+}
+
+// editGoMod parses the go.mod file for gm in repo r, applies fn to it, and
+// returns the edit with the formatted result. Formatting and comments are
+// preserved by the modfile writer.
+func editGoMod(r RepoResult, gm GoModResult, fn func(f *modfile.File, edit *GoModEdit) error) (GoModEdit, error) {
+	edit := GoModEdit{
+		Repo:      r.Name,
+		GoModPath: gm.Path,
+		File:      filepath.Join(r.Path, gm.Path),
+	}
+
+	data, err := os.ReadFile(edit.File)
+	if err != nil {
+		return edit, err
+	}
+	edit.Original = data
+	edit.Updated = data
+
+	f, err := modfile.Parse(edit.File, data, nil)
+	if err != nil {
+		return edit, err
+	}
+
+	if err := fn(f, &edit); err != nil {
+		return edit, err
+	}
+
+	dropRemovedLines(f.Syntax)
+	updated, err := f.Format()
+	if err != nil {
+		return edit, fmt.Errorf("formatting %s: %w", edit.File, err)
+	}
+	if len(edit.Changes) > 0 {
+		edit.Updated = updated
+	}
+	return edit, nil
+}
+
+// dropRemovedLines deletes the lines marked as removed by modfile edits, and
+// blocks left empty. Unlike modfile's Cleanup, blocks left with a single line
+// are not collapsed, so untouched directives keep their formatting.
+func dropRemovedLines(x *modfile.FileSyntax) {
+	w := 0
+	for _, stmt := range x.Stmt {
+		switch stmt := stmt.(type) {
+		case *modfile.Line:
+			if stmt.Token == nil {
+				continue
+			}
+		case *modfile.LineBlock:
+			stmt.Line = slices.DeleteFunc(stmt.Line, func(line *modfile.Line) bool {
+				return line.Token == nil
+			})
+			if len(stmt.Line) == 0 {
+				continue
+			}
+		}
+		x.Stmt[w] = stmt
+		w++
+	}
+	x.Stmt = x.Stmt[:w]
+}
+
+// RemoveLocalReplaces plans edits that drop local-path replace directives
+// pointing at other scanned repos and require the replaced module at the
+// latest version tagged in that repo instead. Existing requirements newer than
+// the tag (e.g., pseudo-versions) are kept. Replaces pointing inside the same
+// repo, outside the scan set, or at repos without a matching tag are skipped.
+// Tags must have been collected during the scan (ScanOptions.CheckTags).
+// Only go.mod files with at least one change or skip are returned.
+func RemoveLocalReplaces(result RepoResult, allResults []RepoResult) ([]GoModEdit, error) {
+	byName := make(map[string]RepoResult)
+	for _, r := range allResults {
+		byName[r.Name] = r
+	}
+
+	var edits []GoModEdit
+	for _, gm := range result.GoMods() {
+		if len(gm.LocalReplaces()) == 0 {
+			continue
+		}
+
+		edit, err := editGoMod(result, gm, func(f *modfile.File, edit *GoModEdit) error {
+			for _, rep := range gm.LocalReplaces() {
+				if rep.TargetRepo == result.Name {
+					continue // Replaces within the same repo (e.g., monorepo modules) are intentional
+				}
+				sibling, ok := byName[rep.TargetRepo]
+				if !ok {
+					edit.Skipped = append(edit.Skipped, fmt.Sprintf("%s => %s: not a scanned repo", rep.OldPath, rep.NewPath))
+					continue
+				}
+
+				moduleDir, err := filepath.Rel(sibling.Path, rep.LocalDir)
+				if err != nil {
+					return err
+				}
+				tagged := LatestModuleVersion(sibling.Tags, moduleDir, rep.OldPath)
+				if tagged == "" {
+					edit.Skipped = append(edit.Skipped, fmt.Sprintf("%s => %s: no semver tag in %s", rep.OldPath, rep.NewPath, sibling.Name))
+					continue
+				}
+
+				if err := f.DropReplace(rep.OldPath, rep.OldVersion); err != nil {
+					return err
+				}
+				edit.Changes = append(edit.Changes, fmt.Sprintf("drop replace %s => %s", rep.OldPath, rep.NewPath))
+
+				req, required := gm.Require(rep.OldPath)
+				if !required || semver.Compare(req.Version, tagged) >= 0 {
+					continue
+				}
+				if err := f.AddRequire(rep.OldPath, tagged); err != nil {
+					return err
+				}
+				edit.Changes = append(edit.Changes, fmt.Sprintf("require %s %s (was %s)", rep.OldPath, tagged, req.Version))
+			}
+			return nil
+		})
+		if err != nil {
+			return edits, err
+		}
+		if len(edit.Changes) > 0 || len(edit.Skipped) > 0 {
+			edits = append(edits, edit)
+		}
+	}
+
+	return edits, nil
+}
+
+// SetRequirement plans edits that require modulePath at version in each of
+// the repo's go.mod files (root and nested) that require an older version.
+// Requirements at or above version are left unchanged; newer ones are reported
+// as skipped. Only go.mod files with at least one change or skip are returned.
+func SetRequirement(result RepoResult, modulePath, version string) ([]GoModEdit, error) {
+	var edits []GoModEdit
+	for _, gm := range result.GoMods() {
+		req, ok := gm.Require(modulePath)
+		if !ok || req.Version == version {
+			continue
+		}
+
+		edit, err := editGoMod(result, gm, func(f *modfile.File, edit *GoModEdit) error {
+			if semver.Compare(req.Version, version) > 0 {
+				edit.Skipped = append(edit.Skipped, fmt.Sprintf("%s %s is newer than %s", modulePath, req.Version, version))
+				return nil
+			}
+			if err := f.AddRequire(modulePath, version); err != nil {
+				return err
+			}
+			edit.Changes = append(edit.Changes, fmt.Sprintf("require %s %s (was %s)", modulePath, version, req.Version))
+			return nil
+		})
+		if err != nil {
+			return edits, err
+		}
+		edits = append(edits, edit)
+	}
+	return edits, nil
+}
+
+// SetGoVersion plans edits that raise the go directive to goVersion (e.g.,
+// "1.25") in each of the repo's go.mod files (root and nested). go directives
+// above goVersion are left unchanged and reported as skipped.
+//
+// A non-empty toolchain (e.g., "go1.25.3") sets the toolchain directive, and
+// "none" drops it. With an empty toolchain, an existing toolchain directive is
+// only dropped if it is older than the resulting go version, as the go
+// command does. Only go.mod files with at least one change or skip are
+// returned.
+func SetGoVersion(result RepoResult, goVersion, toolchain string) ([]GoModEdit, error) {
+	var edits []GoModEdit
+	for _, gm := range result.GoMods() {
+		edit, err := editGoMod(result, gm, func(f *modfile.File, edit *GoModEdit) error {
+			effective := gm.GoVersion
+			switch {
+			case gm.GoVersion == goVersion:
+			case gm.GoVersion != "" && version.Compare("go"+gm.GoVersion, "go"+goVersion) > 0:
+				edit.Skipped = append(edit.Skipped, fmt.Sprintf("go %s is newer than %s", gm.GoVersion, goVersion))
+			default:
+				if err := f.AddGoStmt(goVersion); err != nil {
+					return err
+				}
+				was := gm.GoVersion
+				if was == "" {
+					was = "none"
+				}
+				edit.Changes = append(edit.Changes, fmt.Sprintf("go %s (was %s)", goVersion, was))
+				effective = goVersion
+			}
+
+			switch {
+			case toolchain == "none":
+				if gm.Toolchain != "" {
+					f.DropToolchainStmt()
+					edit.Changes = append(edit.Changes, fmt.Sprintf("drop toolchain %s", gm.Toolchain))
+				}
+			case toolchain == "":
+				if gm.Toolchain != "" && effective != "" && version.Compare(gm.Toolchain, "go"+effective) < 0 {
+					f.DropToolchainStmt()
+					edit.Changes = append(edit.Changes, fmt.Sprintf("drop toolchain %s (older than go %s)", gm.Toolchain, effective))
+				}
+			case toolchain == gm.Toolchain:
+			case effective != "" && version.Compare(toolchain, "go"+effective) < 0:
+				edit.Skipped = append(edit.Skipped, fmt.Sprintf("toolchain %s is older than go %s", toolchain, effective))
+			default:
+				if err := f.AddToolchainStmt(toolchain); err != nil {
+					return err
+				}
+				was := gm.Toolchain
+				if was == "" {
+					was = "none"
+				}
+				edit.Changes = append(edit.Changes, fmt.Sprintf("toolchain %s (was %s)", toolchain, was))
+			}
+			return nil
+		})
+		if err != nil {
+			return edits, err
+		}
+		if len(edit.Changes) > 0 || len(edit.Skipped) > 0 {
+			edits = append(edits, edit)
+		}
+	}
+	return edits, nil
+}
@@ -0,0 +1,327 @@
+package scanner
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+func TestDropRemovedLines(t *testing.T) {
+	const goMod = `module example.com/app
+
+go 1.24
+
+// Kept as a block
+require (
+	example.com/lib v1.2.0
+)
+
+replace example.com/lib => ../lib
+
+replace (
+	example.com/util => ../util // local
+	example.com/other => example.com/fork v1.0.0
+)
+
+replace (
+	example.com/tool => ../tool
+)
+`
+	const want = `module example.com/app
+
+go 1.24
+
+// Kept as a block
+require (
+	example.com/lib v1.2.0
+)
+
+replace (
+	example.com/other => example.com/fork v1.0.0
+)
+`
+	f, err := modfile.Parse("go.mod", []byte(goMod), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"example.com/lib", "example.com/util", "example.com/tool"} {
+		if err := f.DropReplace(path, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dropRemovedLines(f.Syntax)
+	got, err := f.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("after dropRemovedLines:\n%s\nwant\n%s", got, want)
+	}
+}
+
+// goModRepo writes go.mod files (path relative to the repo root => content)
+// into the repo directory dir and returns the analyzed repo.
+func goModRepo(t *testing.T, dir string, files map[string]string) RepoResult {
+	t.Helper()
+	r := RepoResult{Name: filepath.Base(dir), Path: dir}
+	for name, content := range files {
+		writeFile(t, r.Path, name, content)
+		gm, err := analyzeGoMod(filepath.Join(r.Path, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gm.Path = name
+		if name == "go.mod" {
+			r.GoMod = &gm
+		} else {
+			r.GoModFiles = append(r.GoModFiles, gm)
+		}
+	}
+	return r
+}
+
+func TestSetGoVersionRoundTrip(t *testing.T) {
+	const goMod = `// Package app does things.
+module example.com/app
+
+// Minimum supported release
+go 1.22 // keep in sync with CI
+
+toolchain go1.22.5
+
+require (
+	example.com/lib v1.2.0 // pinned
+)
+
+require example.com/util v0.3.0
+
+replace (
+	example.com/lib => ../lib
+)
+`
+	const want = `// Package app does things.
+module example.com/app
+
+// Minimum supported release
+go 1.24 // keep in sync with CI
+
+require (
+	example.com/lib v1.2.0 // pinned
+)
+
+require example.com/util v0.3.0
+
+replace (
+	example.com/lib => ../lib
+)
+`
+	r := goModRepo(t, t.TempDir(), map[string]string{"go.mod": goMod})
+	edits, err := SetGoVersion(r, "1.24", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 {
+		t.Fatalf("SetGoVersion() returned %d edits, want 1", len(edits))
+	}
+	if got := string(edits[0].Updated); got != want {
+		t.Errorf("SetGoVersion() updated go.mod:\n%s\nwant\n%s", got, want)
+	}
+	wantChanges := []string{"go 1.24 (was 1.22)", "drop toolchain go1.22.5 (older than go 1.24)"}
+	if !slices.Equal(edits[0].Changes, wantChanges) {
+		t.Errorf("SetGoVersion() changes = %q, want %q", edits[0].Changes, wantChanges)
+	}
+
+	// Applying the edit and running it again is a no-op
+	if err := edits[0].Apply(); err != nil {
+		t.Fatal(err)
+	}
+	gm, err := analyzeGoMod(edits[0].File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gm.Path = "go.mod"
+	r.GoMod = &gm
+	if edits, err := SetGoVersion(r, "1.24", ""); err != nil || len(edits) != 0 {
+		t.Errorf("second SetGoVersion() = %d edits (err %v), want none", len(edits), err)
+	}
+}
+
+func TestSetGoVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		goMod       string
+		goVersion   string
+		toolchain   string
+		wantChanges []string
+		wantSkipped []string
+	}{
+		{"unchanged", "module m\n\ngo 1.24\n", "1.24", "", nil, nil},
+		{"newer go kept", "module m\n\ngo 1.25\n", "1.24", "", nil, []string{"go 1.25 is newer than 1.24"}},
+		{"missing go added", "module m\n", "1.24", "", []string{"go 1.24 (was none)"}, nil},
+		{"newer toolchain kept", "module m\n\ngo 1.22\n\ntoolchain go1.24.2\n", "1.24", "", []string{"go 1.24 (was 1.22)"}, nil},
+		{"toolchain set", "module m\n\ngo 1.22\n", "1.24", "go1.24.2", []string{"go 1.24 (was 1.22)", "toolchain go1.24.2 (was none)"}, nil},
+		{"toolchain none", "module m\n\ngo 1.24\n\ntoolchain go1.24.2\n", "1.24", "none", []string{"drop toolchain go1.24.2"}, nil},
+		{"toolchain older than go", "module m\n\ngo 1.25\n", "1.24", "go1.24.2", nil, []string{"go 1.25 is newer than 1.24", "toolchain go1.24.2 is older than go 1.25"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := goModRepo(t, t.TempDir(), map[string]string{"go.mod": tt.goMod})
+			edits, err := SetGoVersion(r, tt.goVersion, tt.toolchain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var changes, skipped []string
+			for _, e := range edits {
+				changes = append(changes, e.Changes...)
+				skipped = append(skipped, e.Skipped...)
+			}
+			if !slices.Equal(changes, tt.wantChanges) || !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("SetGoVersion() changes = %q, skipped = %q; want %q, %q", changes, skipped, tt.wantChanges, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestRemoveLocalReplaces(t *testing.T) {
+	ws := t.TempDir()
+	app := goModRepo(t, filepath.Join(ws, "app"), map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.24
+
+require (
+	example.com/lib v1.0.0
+	example.com/util v0.1.0
+	example.com/ext v1.0.0
+	example.com/new v1.5.0-0.20260101000000-abcdefabcdef
+)
+
+replace example.com/lib => ../lib
+
+replace (
+	example.com/util => ../util // dev
+	example.com/ext => ../ext
+)
+
+replace example.com/new => ../new
+`,
+		"tools/go.mod": `module example.com/app/tools
+
+go 1.24
+
+require example.com/app v0.0.0
+
+replace example.com/app => ../
+`,
+	})
+	lib := goModRepo(t, filepath.Join(ws, "lib"), map[string]string{"go.mod": "module example.com/lib\n"})
+	lib.Tags = []string{"v1.1.0", "v1.2.0", "v1.3.0-rc.1"}
+	util := goModRepo(t, filepath.Join(ws, "util"), map[string]string{"go.mod": "module example.com/util\n"})
+	newRepo := goModRepo(t, filepath.Join(ws, "new"), map[string]string{"go.mod": "module example.com/new\n"})
+	newRepo.Tags = []string{"v1.4.0"}
+	all := []RepoResult{app, lib, util, newRepo}
+	resolveLocalReplaces(all)
+
+	edits, err := RemoveLocalReplaces(all[0], all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 {
+		t.Fatalf("RemoveLocalReplaces() returned %d edits, want 1 (the tools replace is within the repo)", len(edits))
+	}
+	edit := edits[0]
+	wantChanges := []string{
+		"drop replace example.com/lib => ../lib",
+		"require example.com/lib v1.2.0 (was v1.0.0)",
+		"drop replace example.com/new => ../new",
+	}
+	wantSkipped := []string{
+		"example.com/util => ../util: no semver tag in util",
+		"example.com/ext => ../ext: not a scanned repo",
+	}
+	if !slices.Equal(edit.Changes, wantChanges) || !slices.Equal(edit.Skipped, wantSkipped) {
+		t.Errorf("RemoveLocalReplaces() changes = %q, skipped = %q; want %q, %q", edit.Changes, edit.Skipped, wantChanges, wantSkipped)
+	}
+
+	const want = `module example.com/app
+
+go 1.24
+
+require (
+	example.com/lib v1.2.0
+	example.com/util v0.1.0
+	example.com/ext v1.0.0
+	example.com/new v1.5.0-0.20260101000000-abcdefabcdef
+)
+
+replace (
+	example.com/util => ../util // dev
+	example.com/ext => ../ext
+)
+`
+	if string(edit.Updated) != want {
+		t.Errorf("RemoveLocalReplaces() updated go.mod:\n%s\nwant\n%s", edit.Updated, want)
+	}
+}
+
+func TestSetRequirement(t *testing.T) {
+	r := goModRepo(t, t.TempDir(), map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.24
+
+require (
+	example.com/lib v1.1.0 // keep
+	example.com/util v0.3.0
+)
+
+require example.com/other v1.0.0 // indirect
+`,
+		"tools/go.mod": "module example.com/app/tools\n\ngo 1.24\n\nrequire example.com/lib v1.3.0\n",
+		"docs/go.mod":  "module example.com/app/docs\n\ngo 1.24\n\nrequire example.com/lib v1.2.0\n",
+		"nolib/go.mod": "module example.com/app/nolib\n\ngo 1.24\n",
+	})
+	edits, err := SetRequirement(r, "example.com/lib", "v1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, e := range edits {
+		got[e.GoModPath] = strings.Join(append(e.Changes, e.Skipped...), "; ")
+	}
+	want := map[string]string{
+		"go.mod":       "require example.com/lib v1.2.0 (was v1.1.0)",
+		"tools/go.mod": "example.com/lib v1.3.0 is newer than v1.2.0",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("SetRequirement() = %q, want %q", got, want)
+	}
+
+	for _, e := range edits {
+		if e.GoModPath == "tools/go.mod" && e.Changed() {
+			t.Errorf("SetRequirement() changed tools/go.mod:\n%s", e.Updated)
+		}
+		if e.GoModPath != "go.mod" {
+			continue
+		}
+		const wantGoMod = `module example.com/app
+
+go 1.24
+
+require (
+	example.com/lib v1.2.0 // keep
+	example.com/util v0.3.0
+)
+
+require example.com/other v1.0.0 // indirect
+`
+		if string(e.Updated) != wantGoMod {
+			t.Errorf("SetRequirement() updated go.mod:\n%s\nwant\n%s", e.Updated, wantGoMod)
+		}
+	}
+}
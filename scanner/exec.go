@@ -0,0 +1,211 @@
+package scanner
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ExecOptions configures running a command across repos.
+type ExecOptions struct {
+	Workers int  // Number of commands run in parallel (0 = GOMAXPROCS)
+	Ordered bool // Run repos after the repos they depend on, skipping dependents of failed repos
+}
+
+// ExecResult is the outcome of running a command in a repo.
+type ExecResult struct {
+	Repo      RepoResult
+	Output    []byte        // Combined stdout and stderr
+	ExitCode  int           // Exit status (-1 if the command could not be started or was skipped)
+	Err       error         // Error starting or running the command
+	Duration  time.Duration // Time taken by the command
+	SkippedBy string        // Failed dependency when skipped (Ordered only)
+}
+
+// Skipped returns true if the command was not run because a dependency failed.
+func (r ExecResult) Skipped() bool {
+	return r.SkippedBy != ""
+}
+
+// Failed returns true if the command could not be run or exited non-zero.
+func (r ExecResult) Failed() bool {
+	return !r.Skipped() && r.Err != nil
+}
+
+// ExecProgressFunc is called as each repo's command finishes or is skipped.
+type ExecProgressFunc func(completed, total int, result ExecResult)
+
+// ExecRepos runs argv in the root directory of each repo using a pool of
+// workers, returning the results in the order of repos. With opts.Ordered, a
+// repo only starts once the selected repos it depends on (directly, or through
+// repos outside the selection in allResults) have succeeded; if one fails, its
+// dependents are skipped. Repos in a dependency cycle do not wait for each
+// other. progressFn (optional) is called from the calling goroutine.
+func ExecRepos(repos, allResults []RepoResult, argv []string, opts ExecOptions, progressFn ExecProgressFunc) []ExecResult {
+	total := len(repos)
+	results := make([]ExecResult, total)
+	if total == 0 {
+		return results
+	}
+
+	deps := make([][]int, total)
+	if opts.Ordered {
+		deps = execDeps(repos, allResults)
+	}
+	dependents := make([][]int, total)
+	remaining := make([]int, total)
+	for i, ds := range deps {
+		remaining[i] = len(ds)
+		for _, d := range ds {
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	numWorkers := opts.Workers
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+	numWorkers = min(numWorkers, total)
+
+	type resultItem struct {
+		index  int
+		result ExecResult
+	}
+
+	// Buffered for every repo so scheduling never blocks on the workers
+	workCh := make(chan int, total)
+	resultCh := make(chan resultItem, total)
+
+	var wg sync.WaitGroup
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range workCh {
+				resultCh <- resultItem{index: i, result: runInRepo(repos[i], argv)}
+			}
+		}()
+	}
+
+	for i := range total {
+		if remaining[i] == 0 {
+			workCh <- i
+		}
+	}
+
+	done := make([]bool, total)
+	completed := 0
+	var complete func(i int, result ExecResult)
+	complete = func(i int, result ExecResult) {
+		done[i] = true
+		results[i] = result
+		completed++
+		if progressFn != nil {
+			progressFn(completed, total, result)
+		}
+
+		for _, d := range dependents[i] {
+			if done[d] {
+				continue
+			}
+			if result.Failed() || result.Skipped() {
+				skippedBy := result.SkippedBy
+				if skippedBy == "" {
+					skippedBy = result.Repo.Name
+				}
+				complete(d, ExecResult{Repo: repos[d], ExitCode: -1, SkippedBy: skippedBy})
+				continue
+			}
+			remaining[d]--
+			if remaining[d] == 0 {
+				workCh <- d
+			}
+		}
+	}
+
+	for completed < total {
+		item := <-resultCh
+		complete(item.index, item.result)
+	}
+	close(workCh)
+	wg.Wait()
+
+	return results
+}
+
+// execDeps returns, for each repo, the indices of the other repos it depends
+// on directly or through repos outside the selection. Dependencies between
+// members of a cycle are dropped so the graph is acyclic.
+func execDeps(repos, allResults []RepoResult) [][]int {
+	_, _, graph := repoGraph(allResults)
+
+	index := make(map[string]int)
+	var names []string
+	for i, r := range repos {
+		index[r.Name] = i
+		names = append(names, r.Name)
+	}
+
+	// Follow dependencies through unselected repos to the nearest selected ones
+	reach := make(map[string][]string)
+	for _, name := range names {
+		visited := map[string]bool{name: true}
+		queue := []string{name}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for dep := range graph[cur] {
+				if visited[dep] {
+					continue
+				}
+				visited[dep] = true
+				if _, selected := index[dep]; selected {
+					reach[name] = append(reach[name], dep)
+					continue
+				}
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	component := make(map[string]int)
+	for c, comp := range stronglyConnected(names, reach) {
+		for _, n := range comp {
+			component[n] = c
+		}
+	}
+
+	deps := make([][]int, len(repos))
+	for _, name := range names {
+		for _, dep := range reach[name] {
+			if component[dep] != component[name] {
+				deps[index[name]] = append(deps[index[name]], index[dep])
+			}
+		}
+	}
+	return deps
+}
+
+// runInRepo runs argv in the repo's root directory.
+func runInRepo(r RepoResult, argv []string) ExecResult {
+	result := ExecResult{Repo: r}
+
+	start := time.Now()
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = r.Path
+	result.Output, result.Err = cmd.CombinedOutput()
+	result.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+	switch {
+	case result.Err == nil:
+		result.ExitCode = 0
+	case errors.As(result.Err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+	}
+	return result
+}
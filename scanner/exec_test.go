@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExecReposOrdered(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	// Diamond: top -> left, right -> base; app -> top; solo has no dependencies.
+	// "hidden" is not selected: via -> hidden -> base.
+	newRepos := func(failing ...string) (repos, all []RepoResult) {
+		all = []RepoResult{
+			workRepo("base", nil),
+			workRepo("left", nil, "base"),
+			workRepo("right", nil, "base"),
+			workRepo("top", nil, "left", "right"),
+			workRepo("app", nil, "top"),
+			workRepo("solo", nil),
+			workRepo("hidden", nil, "base"),
+			workRepo("via", nil, "hidden"),
+		}
+		for i := range all {
+			all[i].Path = t.TempDir()
+			if slices.Contains(failing, all[i].Name) {
+				writeFile(t, all[i].Path, "fail", "")
+			}
+		}
+		for _, r := range all {
+			if r.Name != "hidden" {
+				repos = append(repos, r)
+			}
+		}
+		return repos, all
+	}
+	argv := []string{"sh", "-c", "test ! -e fail"}
+
+	tests := []struct {
+		name    string
+		failing []string
+		want    map[string]string // Repo => "ok", "failed", "skipped by <repo>", or "skipped" (by any failing repo)
+	}{
+		{
+			"all succeed", nil,
+			map[string]string{"base": "ok", "left": "ok", "right": "ok", "top": "ok", "app": "ok", "solo": "ok", "via": "ok"},
+		},
+		{
+			"failed base skips transitive dependents", []string{"base"},
+			map[string]string{"base": "failed", "left": "skipped by base", "right": "skipped by base", "top": "skipped by base", "app": "skipped by base", "solo": "ok", "via": "skipped by base"},
+		},
+		{
+			"failed diamond side", []string{"right"},
+			map[string]string{"base": "ok", "left": "ok", "right": "failed", "top": "skipped by right", "app": "skipped by right", "solo": "ok", "via": "ok"},
+		},
+		{
+			"both diamond sides fail", []string{"left", "right"},
+			map[string]string{"base": "ok", "left": "failed", "right": "failed", "top": "skipped", "app": "skipped", "solo": "ok", "via": "ok"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, all := newRepos(tt.failing...)
+
+			var finished []string
+			progress := func(completed, total int, result ExecResult) {
+				if completed != len(finished)+1 || total != len(repos) {
+					t.Errorf("progress %d/%d after %d results", completed, total, len(finished))
+				}
+				finished = append(finished, result.Repo.Name)
+			}
+			results := ExecRepos(repos, all, argv, ExecOptions{Workers: 3, Ordered: true}, progress)
+
+			got := make(map[string]string)
+			for i, r := range results {
+				if r.Repo.Name != repos[i].Name {
+					t.Errorf("result %d is for %s, want %s", i, r.Repo.Name, repos[i].Name)
+				}
+				switch {
+				case r.Skipped():
+					got[r.Repo.Name] = "skipped by " + r.SkippedBy
+				case r.Failed():
+					got[r.Repo.Name] = "failed"
+				default:
+					got[r.Repo.Name] = "ok"
+				}
+			}
+			for name, want := range tt.want {
+				if by, ok := strings.CutPrefix(got[name], "skipped by "); ok && want == "skipped" && slices.Contains(tt.failing, by) {
+					continue
+				}
+				if got[name] != want {
+					t.Errorf("%s: %s, want %s", name, got[name], want)
+				}
+			}
+
+			// Every repo completes exactly once. Repos that ran complete after
+			// the repos they depend on; skipped repos after the failure.
+			sorted := slices.Sorted(slices.Values(finished))
+			var names []string
+			for _, r := range repos {
+				names = append(names, r.Name)
+			}
+			slices.Sort(names)
+			if !slices.Equal(sorted, names) {
+				t.Fatalf("completed repos = %v, want each of %v once", finished, names)
+			}
+			pos := func(name string) int { return slices.Index(finished, name) }
+			for _, edge := range [][2]string{{"base", "left"}, {"base", "right"}, {"left", "top"}, {"right", "top"}, {"top", "app"}, {"base", "via"}} {
+				if !strings.HasPrefix(got[edge[1]], "skipped") && pos(edge[0]) > pos(edge[1]) {
+					t.Errorf("%s completed before its dependency %s: %v", edge[1], edge[0], finished)
+				}
+			}
+			for _, r := range results {
+				if r.Skipped() && pos(r.SkippedBy) > pos(r.Repo.Name) {
+					t.Errorf("%s skipped before %s failed: %v", r.Repo.Name, r.SkippedBy, finished)
+				}
+			}
+		})
+	}
+}
+
+func TestExecReposUnordered(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	base, app := workRepo("base", nil), workRepo("app", nil, "base")
+	base.Path, app.Path = t.TempDir(), t.TempDir()
+	writeFile(t, base.Path, "fail", "")
+	repos := []RepoResult{base, app}
+
+	results := ExecRepos(repos, repos, []string{"sh", "-c", "test ! -e fail && echo ok"}, ExecOptions{}, nil)
+	if !results[0].Failed() || results[0].ExitCode != 1 {
+		t.Errorf("base: failed %v, exit code %d, want failure with exit code 1", results[0].Failed(), results[0].ExitCode)
+	}
+	if results[1].Failed() || results[1].Skipped() || string(results[1].Output) != "ok\n" {
+		t.Errorf("app: failed %v, skipped %v, output %q; want it to run despite base failing", results[1].Failed(), results[1].Skipped(), results[1].Output)
+	}
+}
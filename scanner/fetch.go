@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultFetchTimeout limits how long fetching a single repo may take when no
+// timeout is configured.
+const DefaultFetchTimeout = 60 * time.Second
+
+// RefChange is a remote-tracking branch that moved during a fetch.
+type RefChange struct {
+	Ref string // Remote-tracking branch, e.g. "origin/main"
+	Old string // Commit before the fetch ("" for new branches)
+	New string // Commit after the fetch ("" for pruned branches)
+}
+
+// FetchOptions configures fetching repos in parallel.
+type FetchOptions struct {
+	Workers int           // Number of repos fetched in parallel (0 = GOMAXPROCS)
+	Timeout time.Duration // Time limit per repo (0 = DefaultFetchTimeout)
+}
+
+// FetchResult is the outcome of fetching a repo.
+type FetchResult struct {
+	Repo     RepoResult
+	Changes  []RefChange // Remote-tracking branches that moved, sorted by ref
+	Err      error
+	Duration time.Duration
+}
+
+// FetchRemotes fetches all remotes of the repo with the given timeout
+// (0 = DefaultFetchTimeout) and returns the remote-tracking branches that
+// moved.
+func FetchRemotes(backend GitBackend, repoPath string, timeout time.Duration) ([]RefChange, error) {
+	if timeout <= 0 {
+		timeout = DefaultFetchTimeout
+	}
+
+	before, err := backend.RemoteRefs(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	fetchErr := backend.Fetch(ctx, repoPath)
+
+	// Report refs updated by a partially successful fetch too
+	after, err := backend.RemoteRefs(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return diffRefs(before, after), fetchErr
+}
+
+// diffRefs returns the refs whose commit differs between before and after.
+func diffRefs(before, after map[string]string) []RefChange {
+	var changes []RefChange
+	for ref, hash := range after {
+		if before[ref] != hash {
+			changes = append(changes, RefChange{Ref: ref, Old: before[ref], New: hash})
+		}
+	}
+	for ref, hash := range before {
+		if _, ok := after[ref]; !ok {
+			changes = append(changes, RefChange{Ref: ref, Old: hash})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Ref < changes[j].Ref
+	})
+	return changes
+}
+
+// FetchRepos fetches all remotes of each git repo using a pool of workers,
+// returning the results in the order of repos. Repos that are not git repos
+// are skipped. progressFn (optional) is called as each repo finishes.
+func FetchRepos(repos []RepoResult, backend GitBackend, opts FetchOptions, progressFn ProgressFunc) []FetchResult {
+	var gitRepos []RepoResult
+	for _, r := range repos {
+		if r.IsGitRepo {
+			gitRepos = append(gitRepos, r)
+		}
+	}
+	total := len(gitRepos)
+	results := make([]FetchResult, total)
+	if total == 0 {
+		return results
+	}
+	if backend == nil {
+		backend = DefaultGitBackend()
+	}
+
+	numWorkers := opts.Workers
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+	numWorkers = min(numWorkers, total)
+
+	workCh := make(chan int, total)
+	doneCh := make(chan int, total)
+	var wg sync.WaitGroup
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range workCh {
+				start := time.Now()
+				changes, err := FetchRemotes(backend, gitRepos[i].Path, opts.Timeout)
+				results[i] = FetchResult{
+					Repo:     gitRepos[i],
+					Changes:  changes,
+					Err:      err,
+					Duration: time.Since(start),
+				}
+				doneCh <- i
+			}
+		}()
+	}
+
+	for i := range total {
+		workCh <- i
+	}
+	close(workCh)
+	go func() {
+		wg.Wait()
+		close(doneCh)
+	}()
+
+	completed := 0
+	for i := range doneCh {
+		completed++
+		if progressFn != nil {
+			progressFn(completed, total, gitRepos[i].Name)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiffRefs(t *testing.T) {
+	before := map[string]string{"origin/main": "a1", "origin/dev": "b1", "origin/old": "c1", "upstream/main": "d1"}
+	after := map[string]string{"origin/main": "a2", "origin/dev": "b1", "origin/new": "e1", "upstream/main": "d1"}
+	want := []RefChange{
+		{Ref: "origin/main", Old: "a1", New: "a2"},
+		{Ref: "origin/new", New: "e1"},
+		{Ref: "origin/old", Old: "c1"},
+	}
+	if got := diffRefs(before, after); !slices.Equal(got, want) {
+		t.Errorf("diffRefs() = %v, want %v", got, want)
+	}
+	if got := diffRefs(before, before); len(got) != 0 {
+		t.Errorf("diffRefs() without changes = %v, want none", got)
+	}
+}
+
+// fetchBackend is a GitBackend whose fetch moves each repo's remote refs
+// from before to after, failing with errs[repoPath] if set.
+type fetchBackend struct {
+	GitBackend
+	before, after map[string]map[string]string
+	errs          map[string]error
+
+	mu        sync.Mutex
+	fetched   map[string]bool
+	deadlines map[string]time.Duration // Remaining time of each fetch's context
+}
+
+func (b *fetchBackend) RemoteRefs(repoPath string) (map[string]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.fetched[repoPath] {
+		return b.after[repoPath], nil
+	}
+	return b.before[repoPath], nil
+}
+
+func (b *fetchBackend) Fetch(ctx context.Context, repoPath string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		b.deadlines[repoPath] = time.Until(deadline)
+	}
+	b.fetched[repoPath] = true
+	return b.errs[repoPath]
+}
+
+func newFetchBackend() *fetchBackend {
+	return &fetchBackend{
+		before: map[string]map[string]string{
+			"/src/a": {"origin/main": "a1"},
+			"/src/b": {"origin/main": "b1"},
+			"/src/c": {"origin/main": "c1"},
+		},
+		after: map[string]map[string]string{
+			"/src/a": {"origin/main": "a2"},
+			"/src/b": {"origin/main": "b1"},
+			"/src/c": {"origin/main": "c2", "origin/dev": "c3"},
+		},
+		errs:      map[string]error{"/src/c": errors.New("remote upstream: connection refused")},
+		fetched:   make(map[string]bool),
+		deadlines: make(map[string]time.Duration),
+	}
+}
+
+func TestFetchRemotes(t *testing.T) {
+	backend := newFetchBackend()
+	changes, err := FetchRemotes(backend, "/src/a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []RefChange{{Ref: "origin/main", Old: "a1", New: "a2"}}; !slices.Equal(changes, want) {
+		t.Errorf("FetchRemotes() = %v, want %v", changes, want)
+	}
+	if d := backend.deadlines["/src/a"]; d <= DefaultFetchTimeout-time.Second || d > DefaultFetchTimeout {
+		t.Errorf("fetch deadline in %v, want DefaultFetchTimeout", d)
+	}
+
+	// Refs moved by a partially failed fetch are still reported
+	changes, err = FetchRemotes(backend, "/src/c", time.Second)
+	if err == nil {
+		t.Error("FetchRemotes() returned no error for a failed fetch")
+	}
+	if len(changes) != 2 {
+		t.Errorf("FetchRemotes() after failed fetch = %v, want 2 changes", changes)
+	}
+	if d := backend.deadlines["/src/c"]; d > time.Second {
+		t.Errorf("fetch deadline in %v, want at most 1s", d)
+	}
+}
+
+func TestFetchRepos(t *testing.T) {
+	backend := newFetchBackend()
+	repos := []RepoResult{
+		{Name: "c", Path: "/src/c", IsGitRepo: true},
+		{Name: "plain", Path: "/src/plain"},
+		{Name: "a", Path: "/src/a", IsGitRepo: true},
+		{Name: "b", Path: "/src/b", IsGitRepo: true},
+	}
+
+	var progress []string
+	results := FetchRepos(repos, backend, FetchOptions{Workers: 2}, func(completed, total int, name string) {
+		if completed != len(progress)+1 || total != 3 {
+			t.Errorf("progress %d/%d after %d repos", completed, total, len(progress))
+		}
+		progress = append(progress, name)
+	})
+
+	var got []string
+	for _, r := range results {
+		s := r.Repo.Name
+		for _, c := range r.Changes {
+			s += " " + c.Ref
+		}
+		if r.Err != nil {
+			s += " (failed)"
+		}
+		got = append(got, s)
+	}
+	if want := []string{"c origin/dev origin/main (failed)", "a origin/main", "b"}; !slices.Equal(got, want) {
+		t.Errorf("FetchRepos() = %q, want %q", got, want)
+	}
+	slices.Sort(progress)
+	if !slices.Equal(progress, []string{"a", "b", "c"}) {
+		t.Errorf("progress reported for %v, want a, b, c", progress)
+	}
+	if backend.fetched["/src/plain"] {
+		t.Error("FetchRepos() fetched a directory that is not a git repo")
+	}
+}
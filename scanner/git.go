@@ -1,8 +1,18 @@
 package scanner
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 // GitBackend provides git operations for repository scanning.
@@ -10,7 +20,53 @@ type GitBackend interface {
 	// IsRepo checks if the path is a git repository.
 	IsRepo(path string) bool
 	// GetStatus returns uncommitted changes and unpushed commits status.
-	GetStatus(repoPath string, checkUnpushed bool) (hasUncommitted, hasUnpushed bool)
+	// A non-nil error means the status could not be determined.
+	GetStatus(repoPath string, checkUnpushed bool) (hasUncommitted, hasUnpushed bool, err error)
+	// ListTags returns the names of all tags in the repository (e.g., "v1.2.3").
+	ListTags(repoPath string) ([]string, error)
+	// IsTracked checks if the file (relative to the repo root) is committed to the index.
+	IsTracked(repoPath, file string) (bool, error)
+	// CommitsSince returns the number of commits reachable from HEAD but not from ref
+	// (e.g., "refs/tags/v1.2.3").
+	CommitsSince(repoPath, ref string) (int, error)
+	// CommitMessages returns the full messages of commits reachable from HEAD
+	// but not from ref, newest first. An empty ref returns all commits.
+	CommitMessages(repoPath, ref string) ([]string, error)
+	// HeadTags returns the names of tags pointing at HEAD.
+	HeadTags(repoPath string) ([]string, error)
+	// Fetch fetches all remotes, pruning remote-tracking branches deleted on
+	// the remote. It stops when ctx is done.
+	Fetch(ctx context.Context, repoPath string) error
+	// RemoteRefs returns the remote-tracking branches (e.g., "origin/main")
+	// mapped to their commit hashes.
+	RemoteRefs(repoPath string) (map[string]string, error)
+	// Upstream returns the current branch and how far it is ahead of and
+	// behind its configured upstream branch.
+	Upstream(repoPath string) (UpstreamStatus, error)
+	// FastForward advances the current branch to its upstream branch,
+	// failing if that is not a fast-forward.
+	FastForward(repoPath string) error
+	// Remotes returns the configured remotes with their URLs, sorted by name.
+	Remotes(repoPath string) ([]Remote, error)
+	// Push pushes the local branch to remoteBranch on remote, and all tags
+	// when tags is set. It stops when ctx is done.
+	Push(ctx context.Context, repoPath, remote, branch, remoteBranch string, tags bool) error
+}
+
+// Remote is a configured git remote.
+type Remote struct {
+	Name string   // Remote name, e.g. "origin"
+	URLs []string // Fetch URLs as configured
+}
+
+// UpstreamStatus is the current branch compared with its upstream branch.
+type UpstreamStatus struct {
+	Branch   string // Current branch ("" for a detached HEAD)
+	Upstream string // Configured upstream, e.g. "origin/main" ("" if none)
+	Remote   string // Remote of the upstream, e.g. "origin" ("." for a local branch)
+	Gone     bool   // The upstream is configured but its ref does not exist
+	Ahead    int    // Commits on Branch not on Upstream
+	Behind   int    // Commits on Upstream not on Branch
 }
 
 // GoGitBackend implements GitBackend using go-git (pure Go, no process spawning).
@@ -28,21 +84,21 @@ func (g *GoGitBackend) IsRepo(path string) bool {
 }
 
 // GetStatus returns uncommitted changes and unpushed commits status using go-git.
-func (g *GoGitBackend) GetStatus(repoPath string, checkUnpushed bool) (hasUncommitted, hasUnpushed bool) {
+func (g *GoGitBackend) GetStatus(repoPath string, checkUnpushed bool) (hasUncommitted, hasUnpushed bool, err error) {
 	repo, err := git.PlainOpen(repoPath)
 	if err != nil {
-		return false, false
+		return false, false, fmt.Errorf("git open: %w", err)
 	}
 
 	// Check for uncommitted changes
 	worktree, err := repo.Worktree()
 	if err != nil {
-		return false, false
+		return false, false, fmt.Errorf("git worktree: %w", err)
 	}
 
 	status, err := worktree.Status()
 	if err != nil {
-		return false, false
+		return false, false, fmt.Errorf("git status: %w", err)
 	}
 
 	hasUncommitted = !status.IsClean()
@@ -52,7 +108,7 @@ func (g *GoGitBackend) GetStatus(repoPath string, checkUnpushed bool) (hasUncomm
 		hasUnpushed = g.hasUnpushedCommits(repo)
 	}
 
-	return hasUncommitted, hasUnpushed
+	return hasUncommitted, hasUnpushed, nil
 }
 
 // hasUnpushedCommits checks if HEAD is ahead of its upstream tracking branch.
@@ -94,17 +150,396 @@ func (g *GoGitBackend) hasUnpushedCommits(repo *git.Repository) bool {
 		return false
 	}
 
-	// Check if remote commit is ancestor of HEAD (we're ahead)
+	// HEAD has unpushed commits unless it is contained in the remote branch
+	// (HEAD is behind, not ahead); this includes diverged branches
 	isAncestor, err := headCommit.IsAncestor(remoteCommit)
 	if err != nil {
 		return true // Error checking, assume unpushed
 	}
 
-	// If remote is ancestor of HEAD, we have unpushed commits
-	return isAncestor
+	return !isAncestor
+}
+
+// ListTags returns the names of all tags in the repository using go-git.
+func (g *GoGitBackend) ListTags(repoPath string) ([]string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("git open: %w", err)
+	}
+
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("git tags: %w", err)
+	}
+
+	var tags []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		tags = append(tags, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("git tags: %w", err)
+	}
+	return tags, nil
+}
+
+// IsTracked checks if the file is in the git index using go-git.
+func (g *GoGitBackend) IsTracked(repoPath, file string) (bool, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return false, fmt.Errorf("git open: %w", err)
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return false, fmt.Errorf("git index: %w", err)
+	}
+
+	if _, err := idx.Entry(filepath.ToSlash(file)); err != nil {
+		if errors.Is(err, index.ErrEntryNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("git index: %w", err)
+	}
+	return true, nil
+}
+
+// CommitsSince counts commits reachable from HEAD but not from ref using go-git.
+func (g *GoGitBackend) CommitsSince(repoPath, ref string) (int, error) {
+	commits, err := g.commitsSince(repoPath, ref)
+	return len(commits), err
+}
+
+// CommitMessages returns the messages of commits reachable from HEAD but not from ref using go-git.
+func (g *GoGitBackend) CommitMessages(repoPath, ref string) ([]string, error) {
+	commits, err := g.commitsSince(repoPath, ref)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]string, 0, len(commits))
+	for _, c := range commits {
+		messages = append(messages, c.Message)
+	}
+	return messages, nil
+}
+
+// commitsSince returns the commits reachable from HEAD but not from ref,
+// newest first. An empty ref returns all commits reachable from HEAD.
+func (g *GoGitBackend) commitsSince(repoPath, ref string) ([]*object.Commit, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("git open: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("git head: %w", err)
+	}
+
+	var base plumbing.Hash
+	if ref != "" {
+		if base, err = resolveCommit(repo, ref); err != nil {
+			return nil, err
+		}
+	}
+	return commitsExcluding(repo, head.Hash(), base)
+}
+
+// commitsExcluding returns the commits reachable from from but not from
+// exclude, newest first. A zero exclude hash excludes nothing.
+func commitsExcluding(repo *git.Repository, from, exclude plumbing.Hash) ([]*object.Commit, error) {
+	// Mark commits reachable from exclude
+	base := make(map[plumbing.Hash]bool)
+	if !exclude.IsZero() {
+		baseIter, err := repo.Log(&git.LogOptions{From: exclude})
+		if err != nil {
+			return nil, fmt.Errorf("git log: %w", err)
+		}
+		err = baseIter.ForEach(func(c *object.Commit) error {
+			base[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("git log: %w", err)
+		}
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	var commits []*object.Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if !base[c.Hash] {
+			commits = append(commits, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return commits, nil
+}
+
+// HeadTags returns the names of tags pointing at HEAD using go-git.
+// Annotated tags are peeled to the commit they point at.
+func (g *GoGitBackend) HeadTags(repoPath string) ([]string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("git open: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("git head: %w", err)
+	}
+
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("git tags: %w", err)
+	}
+
+	var tags []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		hash, err := resolveCommit(repo, ref.Name().String())
+		if err != nil {
+			return nil // Tags of non-commit objects cannot point at HEAD
+		}
+		if hash == head.Hash() {
+			tags = append(tags, ref.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("git tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Fetch fetches all remotes using go-git. Remotes that are already up to date
+// are not an error. Only remotes without authentication (e.g., file:// or
+// public https) can be fetched.
+func (g *GoGitBackend) Fetch(ctx context.Context, repoPath string) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("git open: %w", err)
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return fmt.Errorf("git remotes: %w", err)
+	}
+	for _, remote := range remotes {
+		name := remote.Config().Name
+		err := remote.FetchContext(ctx, &git.FetchOptions{RemoteName: name, Prune: true})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return fmt.Errorf("git fetch %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// RemoteRefs returns the remote-tracking branches using go-git.
+// Symbolic refs such as origin/HEAD are skipped.
+func (g *GoGitBackend) RemoteRefs(repoPath string) (map[string]string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("git open: %w", err)
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("git references: %w", err)
+	}
+	remoteRefs := make(map[string]string)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().IsRemote() && ref.Type() == plumbing.HashReference {
+			remoteRefs[ref.Name().Short()] = ref.Hash().String()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("git references: %w", err)
+	}
+	return remoteRefs, nil
+}
+
+// Upstream compares the current branch with the upstream configured in the
+// repo's branch settings using go-git.
+func (g *GoGitBackend) Upstream(repoPath string) (UpstreamStatus, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return UpstreamStatus{}, fmt.Errorf("git open: %w", err)
+	}
+	status, head, upstream, err := g.upstream(repo)
+	if err != nil || upstream == nil {
+		return status, err
+	}
+
+	ahead, err := commitsExcluding(repo, head.Hash(), upstream.Hash())
+	if err != nil {
+		return status, err
+	}
+	behind, err := commitsExcluding(repo, upstream.Hash(), head.Hash())
+	if err != nil {
+		return status, err
+	}
+	status.Ahead, status.Behind = len(ahead), len(behind)
+	return status, nil
+}
+
+// FastForward resets the current branch to its upstream using go-git, after
+// checking that the working tree is clean and HEAD is an ancestor of the
+// upstream.
+func (g *GoGitBackend) FastForward(repoPath string) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("git open: %w", err)
+	}
+	status, head, upstream, err := g.upstream(repo)
+	if err != nil {
+		return err
+	}
+	if upstream == nil {
+		return fmt.Errorf("no upstream for branch %q", status.Branch)
+	}
+
+	ahead, err := commitsExcluding(repo, head.Hash(), upstream.Hash())
+	if err != nil {
+		return err
+	}
+	if len(ahead) > 0 {
+		return fmt.Errorf("not a fast-forward: %s is %d commits ahead of %s", status.Branch, len(ahead), status.Upstream)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("git worktree: %w", err)
+	}
+	wtStatus, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("git status: %w", err)
+	}
+	if !wtStatus.IsClean() {
+		return errors.New("working tree has uncommitted changes")
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: upstream.Hash(), Mode: git.MergeReset}); err != nil {
+		return fmt.Errorf("git reset: %w", err)
+	}
+	return nil
+}
+
+// Remotes returns the configured remotes using go-git.
+func (g *GoGitBackend) Remotes(repoPath string) ([]Remote, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("git open: %w", err)
+	}
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("git remotes: %w", err)
+	}
+
+	result := make([]Remote, 0, len(remotes))
+	for _, remote := range remotes {
+		cfg := remote.Config()
+		result = append(result, Remote{Name: cfg.Name, URLs: cfg.URLs})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// Push pushes the branch (and all tags) using go-git. A remote that is
+// already up to date is not an error. Only remotes without authentication
+// (e.g., file:// remotes) can be pushed to.
+func (g *GoGitBackend) Push(ctx context.Context, repoPath, remote, branch, remoteBranch string, tags bool) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("git open: %w", err)
+	}
+
+	refSpecs := []config.RefSpec{
+		config.RefSpec(plumbing.NewBranchReferenceName(branch) + ":" + plumbing.NewBranchReferenceName(remoteBranch)),
+	}
+	if tags {
+		refSpecs = append(refSpecs, "refs/tags/*:refs/tags/*")
+	}
+	err = repo.PushContext(ctx, &git.PushOptions{RemoteName: remote, RefSpecs: refSpecs})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("git push %s: %w", remote, err)
+	}
+	return nil
+}
+
+// upstream resolves HEAD and the upstream ref of the current branch. The
+// upstream ref is nil for a detached HEAD, a branch without an upstream, or
+// an upstream whose ref does not exist (Gone).
+func (g *GoGitBackend) upstream(repo *git.Repository) (UpstreamStatus, *plumbing.Reference, *plumbing.Reference, error) {
+	var status UpstreamStatus
+	head, err := repo.Head()
+	if err != nil {
+		return status, nil, nil, fmt.Errorf("git head: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return status, head, nil, nil
+	}
+	status.Branch = head.Name().Short()
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return status, head, nil, fmt.Errorf("git config: %w", err)
+	}
+	branch, ok := cfg.Branches[status.Branch]
+	if !ok || branch.Remote == "" || branch.Merge == "" {
+		return status, head, nil, nil
+	}
+
+	refName := plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short())
+	status.Upstream = branch.Remote + "/" + branch.Merge.Short()
+	status.Remote = branch.Remote
+	if branch.Remote == "." {
+		refName = branch.Merge
+		status.Upstream = branch.Merge.Short()
+	}
+	upstream, err := repo.Reference(refName, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		status.Gone = true
+		return status, head, nil, nil
+	}
+	if err != nil {
+		return status, head, nil, fmt.Errorf("git upstream: %w", err)
+	}
+	return status, head, upstream, nil
+}
+
+// resolveCommit resolves ref to a commit hash, peeling annotated tags.
+func resolveCommit(repo *git.Repository, ref string) (plumbing.Hash, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("git resolve %s: %w", ref, err)
+	}
+	if tag, err := repo.TagObject(*hash); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("git resolve %s: %w", ref, err)
+		}
+		return commit.Hash, nil
+	}
+	return *hash, nil
 }
 
 // DefaultGitBackend returns the default git backend (go-git).
 func DefaultGitBackend() GitBackend {
 	return NewGoGitBackend()
 }
+
+// RemoteBranch returns the upstream's branch name on its remote, e.g. "main"
+// for "origin/main".
+func (u UpstreamStatus) RemoteBranch() string {
+	if u.Remote == "." {
+		return u.Upstream
+	}
+	return strings.TrimPrefix(u.Upstream, u.Remote+"/")
+}
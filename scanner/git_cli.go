@@ -1,9 +1,14 @@
 package scanner
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -30,16 +35,15 @@ func (c *CLIGitBackend) IsRepo(path string) bool {
 // Output format:
 //   - First line: ## branch...upstream [ahead N, behind M]
 //   - Remaining lines: file status (if any uncommitted changes)
-func (c *CLIGitBackend) GetStatus(repoPath string, checkUnpushed bool) (hasUncommitted, hasUnpushed bool) {
-	cmd := exec.Command("git", "-C", repoPath, "status", "--porcelain", "-b")
-	output, err := cmd.Output()
+func (c *CLIGitBackend) GetStatus(repoPath string, checkUnpushed bool) (hasUncommitted, hasUnpushed bool, err error) {
+	output, err := runGit(repoPath, "status", "--porcelain", "-b")
 	if err != nil {
-		return false, false
+		return false, false, err
 	}
 
 	lines := strings.Split(string(output), "\n")
 	if len(lines) == 0 {
-		return false, false
+		return false, false, nil
 	}
 
 	// First line is branch info: ## main...origin/main [ahead 1]
@@ -64,5 +68,202 @@ func (c *CLIGitBackend) GetStatus(repoPath string, checkUnpushed bool) (hasUncom
 		}
 	}
 
-	return hasUncommitted, hasUnpushed
+	return hasUncommitted, hasUnpushed, nil
+}
+
+// ListTags uses `git tag --list` to return the names of all tags in the repository.
+func (c *CLIGitBackend) ListTags(repoPath string) ([]string, error) {
+	output, err := runGit(repoPath, "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// IsTracked uses `git ls-files` to check if the file is in the git index.
+func (c *CLIGitBackend) IsTracked(repoPath, file string) (bool, error) {
+	output, err := runGit(repoPath, "ls-files", "--", file)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// CommitsSince uses `git rev-list --count` to count commits reachable from HEAD but not from ref.
+func (c *CLIGitBackend) CommitsSince(repoPath, ref string) (int, error) {
+	output, err := runGit(repoPath, "rev-list", "--count", ref+"..HEAD")
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("git rev-list: %w", err)
+	}
+	return count, nil
+}
+
+// CommitMessages uses `git log` to return the messages of commits reachable from HEAD but not from ref.
+func (c *CLIGitBackend) CommitMessages(repoPath, ref string) ([]string, error) {
+	revRange := "HEAD"
+	if ref != "" {
+		revRange = ref + "..HEAD"
+	}
+	output, err := runGit(repoPath, "log", "--format=%B%x00", revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for msg := range strings.SplitSeq(string(output), "\x00") {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+// HeadTags uses `git tag --points-at` to return the names of tags pointing at HEAD.
+func (c *CLIGitBackend) HeadTags(repoPath string) ([]string, error) {
+	output, err := runGit(repoPath, "tag", "--points-at", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// Fetch uses `git fetch --all --prune` to fetch all remotes. Credential
+// prompts are disabled so a remote requiring input fails instead of blocking.
+func (c *CLIGitBackend) Fetch(ctx context.Context, repoPath string) error {
+	_, err := runGitContext(ctx, repoPath, "fetch", "--all", "--prune", "--quiet")
+	return err
+}
+
+// RemoteRefs uses `git for-each-ref refs/remotes` to return the remote-tracking
+// branches. Symbolic refs such as origin/HEAD are skipped.
+func (c *CLIGitBackend) RemoteRefs(repoPath string) (map[string]string, error) {
+	output, err := runGit(repoPath, "for-each-ref", "--format=%(refname) %(objectname) %(symref)", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	for line := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue // Empty output or a symbolic ref
+		}
+		refs[strings.TrimPrefix(fields[0], "refs/remotes/")] = fields[1]
+	}
+	return refs, nil
+}
+
+// Upstream uses `git status --porcelain=v2 --branch` to compare the current
+// branch with its upstream.
+func (c *CLIGitBackend) Upstream(repoPath string) (UpstreamStatus, error) {
+	var status UpstreamStatus
+	output, err := runGit(repoPath, "status", "--porcelain=v2", "--branch", "--untracked-files=no")
+	if err != nil {
+		return status, err
+	}
+
+	hasAheadBehind := false
+	for line := range strings.SplitSeq(string(output), "\n") {
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "# "), " ")
+		if !ok || !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		switch key {
+		case "branch.head":
+			if value != "(detached)" {
+				status.Branch = value
+			}
+		case "branch.upstream":
+			status.Upstream = value
+		case "branch.ab":
+			// Format: +<ahead> -<behind>
+			if _, err := fmt.Sscanf(value, "+%d -%d", &status.Ahead, &status.Behind); err != nil {
+				return status, fmt.Errorf("git status: unexpected branch.ab %q", value)
+			}
+			hasAheadBehind = true
+		}
+	}
+	status.Gone = status.Upstream != "" && !hasAheadBehind
+
+	if status.Upstream != "" {
+		remote, err := runGit(repoPath, "config", "--get", "branch."+status.Branch+".remote")
+		if err != nil {
+			return status, err
+		}
+		status.Remote = strings.TrimSpace(string(remote))
+	}
+	return status, nil
+}
+
+// FastForward uses `git merge --ff-only @{upstream}` to advance the current
+// branch without fetching.
+func (c *CLIGitBackend) FastForward(repoPath string) error {
+	_, err := runGit(repoPath, "merge", "--ff-only", "--quiet", "@{upstream}")
+	return err
+}
+
+// Remotes uses `git remote -v` to return the configured remotes and their
+// fetch URLs.
+func (c *CLIGitBackend) Remotes(repoPath string) ([]Remote, error) {
+	output, err := runGit(repoPath, "remote", "-v")
+	if err != nil {
+		return nil, err
+	}
+
+	// Format: <name>\t<url> (fetch)
+	var remotes []Remote
+	for line := range strings.SplitSeq(string(output), "\n") {
+		name, rest, ok := strings.Cut(line, "\t")
+		url, found := strings.CutSuffix(rest, " (fetch)")
+		if !ok || !found {
+			continue
+		}
+		if n := len(remotes); n > 0 && remotes[n-1].Name == name {
+			remotes[n-1].URLs = append(remotes[n-1].URLs, url)
+			continue
+		}
+		remotes = append(remotes, Remote{Name: name, URLs: []string{url}})
+	}
+	sort.Slice(remotes, func(i, j int) bool {
+		return remotes[i].Name < remotes[j].Name
+	})
+	return remotes, nil
+}
+
+// Push uses `git push <remote> <branch>:<remoteBranch>` (with --tags when
+// tags is set). Credential prompts are disabled.
+func (c *CLIGitBackend) Push(ctx context.Context, repoPath, remote, branch, remoteBranch string, tags bool) error {
+	args := []string{"push", "--quiet", remote, "refs/heads/" + branch + ":refs/heads/" + remoteBranch}
+	if tags {
+		args = append(args, "--tags")
+	}
+	_, err := runGitContext(ctx, repoPath, args...)
+	return err
+}
+
+// runGit runs a git command in repoPath and returns its stdout.
+// On failure, the returned error includes git's stderr output.
+func runGit(repoPath string, args ...string) ([]byte, error) {
+	return runGitContext(context.Background(), repoPath, args...)
+}
+
+// runGitContext is runGit with a context that kills git when done.
+func runGitContext(ctx context.Context, repoPath string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repoPath}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.Output()
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return nil, fmt.Errorf("git %s: %w", args[0], ctxErr)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return output, nil
 }
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGoGitUnpushedCommits(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	when := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(msg string) plumbing.Hash {
+		when = when.Add(time.Minute)
+		h, err := wt.Commit(msg, &git.CommitOptions{
+			AllowEmptyCommits: true,
+			Author:            &object.Signature{Name: "test", Email: "test@example.com", When: when},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	setRef := func(name plumbing.ReferenceName, h plumbing.Hash) {
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(name, h)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// first <- second on the branch, first <- other on a diverged line
+	first := commit("first")
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch := head.Name()
+	other := commit("other")
+	setRef(branch, first)
+	second := commit("second")
+	remote := plumbing.NewRemoteReferenceName("origin", branch.Short())
+
+	tests := []struct {
+		name   string
+		head   plumbing.Hash
+		remote plumbing.Hash // Zero for no remote branch
+		want   bool
+	}{
+		{"no remote branch", second, plumbing.ZeroHash, true},
+		{"up to date", second, second, false},
+		{"ahead", second, first, true},
+		{"behind", first, second, false},
+		{"diverged", second, other, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRef(branch, tt.head)
+			if err := repo.Storer.RemoveReference(remote); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.remote.IsZero() {
+				setRef(remote, tt.remote)
+			}
+			_, unpushed, err := NewGoGitBackend().GetStatus(dir, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if unpushed != tt.want {
+				t.Errorf("GetStatus() unpushed = %v, want %v", unpushed, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// GoModResult holds analysis results for a single go.mod file.
+type GoModResult struct {
+	Path         string          // Path to go.mod relative to repo root
+	ModuleName   string          // Module name from go.mod
+	GoVersion    string          // Version from the go directive (e.g., "1.22")
+	Toolchain    string          // Toolchain directive (e.g., "go1.22.3")
+	Requires     []Require       // Require directives with versions
+	Replaces     []Replace       // Replace directives
+	Excludes     []ModuleVersion // Exclude directives
+	Retracts     []Retract       // Retract directives
+	Tools        []string        // Tool directives (package paths)
+	Godebugs     []Godebug       // Godebug directives
+	Dependencies []string        // Required module paths
+	ReplaceCount int             // Number of replace directives
+}
+
+// ModuleVersion is a module path and version pair.
+type ModuleVersion struct {
+	Path    string
+	Version string
+}
+
+// Require is a single require directive.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool // Marked with "// indirect"
+	Line     int  // Line number in go.mod
+}
+
+// ReplaceKind classifies the target of a replace directive.
+type ReplaceKind int
+
+const (
+	ReplaceLocalPath  ReplaceKind = iota // => ../mogo (local filesystem directory)
+	ReplaceFork                          // => github.com/acme/fork v1.2.3 (different module)
+	ReplaceVersionPin                    // => same module path at a specific version
+)
+
+// String returns a short name for the replace kind.
+func (k ReplaceKind) String() string {
+	switch k {
+	case ReplaceLocalPath:
+		return "local"
+	case ReplaceFork:
+		return "fork"
+	case ReplaceVersionPin:
+		return "pin"
+	default:
+		return "unknown"
+	}
+}
+
+// Replace is a single replace directive: Old => New.
+// OldVersion is empty when the directive applies to all versions.
+// NewVersion is empty when New is a local filesystem path.
+type Replace struct {
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+	Kind       ReplaceKind
+	LocalDir   string // Target directory resolved against the go.mod directory (local-path replaces only)
+	DirExists  bool   // LocalDir exists and is a directory (local-path replaces only)
+	TargetRepo string // Scanned repo containing LocalDir, if any (local-path replaces only)
+}
+
+// IsLocal returns true if the replace points at a local filesystem path.
+func (r Replace) IsLocal() bool {
+	return r.Kind == ReplaceLocalPath
+}
+
+// Retract is a single retracted version or version range.
+// Low and High are equal for a single retracted version.
+type Retract struct {
+	Low       string
+	High      string
+	Rationale string
+}
+
+// Godebug is a single godebug key=value setting.
+type Godebug struct {
+	Key   string
+	Value string
+}
+
+// Require returns the require directive for the given module path, if present.
+func (g GoModResult) Require(modulePath string) (Require, bool) {
+	for _, req := range g.Requires {
+		if req.Path == modulePath {
+			return req, true
+		}
+	}
+	return Require{}, false
+}
+
+// Replacement returns the replace directive that applies to modulePath at
+// version, if any. A replace of that specific version takes precedence over
+// one for all versions, as in the go command.
+func (g GoModResult) Replacement(modulePath, version string) (Replace, bool) {
+	var found Replace
+	ok := false
+	for _, r := range g.Replaces {
+		if r.OldPath != modulePath {
+			continue
+		}
+		switch r.OldVersion {
+		case version:
+			return r, true
+		case "":
+			found, ok = r, true
+		}
+	}
+	return found, ok
+}
+
+// LocalReplaces returns the replace directives that point at local filesystem paths.
+func (g GoModResult) LocalReplaces() []Replace {
+	var local []Replace
+	for _, r := range g.Replaces {
+		if r.IsLocal() {
+			local = append(local, r)
+		}
+	}
+	return local
+}
+
+// MissingReplaces returns the local-path replace directives whose target
+// directory does not exist.
+func (g GoModResult) MissingReplaces() []Replace {
+	var missing []Replace
+	for _, r := range g.LocalReplaces() {
+		if !r.DirExists {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
+// analyzeGoMod parses the go.mod file at goModPath. If the file does not pass
+// strict parsing, it falls back to lax parsing (which only reads the module,
+// go, toolchain, require and retract directives) and returns the strict
+// parse error alongside the partial result.
+func analyzeGoMod(goModPath string) (GoModResult, error) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return GoModResult{}, err
+	}
+
+	f, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		lax, laxErr := modfile.ParseLax(goModPath, data, nil)
+		if laxErr != nil {
+			return GoModResult{}, err
+		}
+		return newGoModResult(lax, filepath.Dir(goModPath)), err
+	}
+
+	return newGoModResult(f, filepath.Dir(goModPath)), nil
+}
+
+// newGoModResult converts a parsed modfile into a GoModResult.
+// goModDir is the directory containing the go.mod, used to resolve
+// local-path replace targets.
+func newGoModResult(f *modfile.File, goModDir string) GoModResult {
+	var result GoModResult
+
+	if f.Module != nil {
+		result.ModuleName = f.Module.Mod.Path
+	}
+	if f.Go != nil {
+		result.GoVersion = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		result.Toolchain = f.Toolchain.Name
+	}
+
+	for _, r := range f.Require {
+		result.Requires = append(result.Requires, Require{
+			Path:     r.Mod.Path,
+			Version:  r.Mod.Version,
+			Indirect: r.Indirect,
+			Line:     r.Syntax.Start.Line,
+		})
+		result.Dependencies = append(result.Dependencies, r.Mod.Path)
+	}
+
+	for _, r := range f.Replace {
+		result.Replaces = append(result.Replaces, newReplace(r, goModDir))
+	}
+	result.ReplaceCount = len(result.Replaces)
+
+	for _, e := range f.Exclude {
+		result.Excludes = append(result.Excludes, ModuleVersion{Path: e.Mod.Path, Version: e.Mod.Version})
+	}
+
+	for _, r := range f.Retract {
+		result.Retracts = append(result.Retracts, Retract{
+			Low:       r.Low,
+			High:      r.High,
+			Rationale: r.Rationale,
+		})
+	}
+
+	for _, t := range f.Tool {
+		result.Tools = append(result.Tools, t.Path)
+	}
+
+	for _, g := range f.Godebug {
+		result.Godebugs = append(result.Godebugs, Godebug{Key: g.Key, Value: g.Value})
+	}
+
+	return result
+}
+
+// newReplace converts a parsed replace directive and classifies its target.
+// A target without a version is always a local path (modfile enforces this).
+func newReplace(r *modfile.Replace, goModDir string) Replace {
+	rep := Replace{
+		OldPath:    r.Old.Path,
+		OldVersion: r.Old.Version,
+		NewPath:    r.New.Path,
+		NewVersion: r.New.Version,
+	}
+
+	switch {
+	case r.New.Version == "":
+		rep.Kind = ReplaceLocalPath
+		rep.LocalDir = r.New.Path
+		if !filepath.IsAbs(rep.LocalDir) {
+			rep.LocalDir = filepath.Join(goModDir, rep.LocalDir)
+		}
+		rep.LocalDir = filepath.Clean(rep.LocalDir)
+		if info, err := os.Stat(rep.LocalDir); err == nil && info.IsDir() {
+			rep.DirExists = true
+		}
+	case r.New.Path == r.Old.Path:
+		rep.Kind = ReplaceVersionPin
+	default:
+		rep.Kind = ReplaceFork
+	}
+
+	return rep
+}
+
+// resolveLocalReplaces sets TargetRepo on local-path replace directives whose
+// target directory is (or is inside) one of the scanned repos.
+func resolveLocalReplaces(results []RepoResult) {
+	resolve := func(gm *GoModResult) {
+		for i := range gm.Replaces {
+			if gm.Replaces[i].IsLocal() {
+				gm.Replaces[i].TargetRepo = FindRepoForDir(gm.Replaces[i].LocalDir, results)
+			}
+		}
+	}
+
+	for i := range results {
+		if results[i].GoMod != nil {
+			resolve(results[i].GoMod)
+		}
+		for j := range results[i].GoModFiles {
+			resolve(&results[i].GoModFiles[j])
+		}
+	}
+}
+
+// FindRepoForDir returns the name of the scanned repo that is or contains dir,
+// or "" if dir is outside all scanned repos.
+func FindRepoForDir(dir string, results []RepoResult) string {
+	for _, r := range results {
+		if dir == r.Path || strings.HasPrefix(dir, r.Path+string(filepath.Separator)) {
+			return r.Name
+		}
+	}
+	return ""
+}
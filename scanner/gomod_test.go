@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeGoMod(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "lib/go.mod", "module example.com/lib\n")
+	writeFile(t, dir, "app/go.mod", `module example.com/app
+
+go 1.24
+
+toolchain go1.24.2
+
+godebug default=go1.21
+
+require (
+	example.com/lib v1.2.0
+	example.com/util v0.3.0 // indirect
+)
+
+require example.com/fork v1.0.0
+
+exclude example.com/util v0.2.0
+
+replace example.com/lib => ../lib
+
+replace (
+	example.com/gone => ./missing
+	example.com/util v0.3.0 => example.com/util v0.3.1
+	example.com/fork => github.com/acme/fork v1.0.1
+)
+
+retract (
+	v1.0.0 // published by mistake
+	[v0.9.0, v0.9.5]
+)
+
+tool example.com/lib/cmd/gen
+`)
+
+	gm, err := analyzeGoMod(filepath.Join(dir, "app/go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gm.ModuleName != "example.com/app" || gm.GoVersion != "1.24" || gm.Toolchain != "go1.24.2" {
+		t.Errorf("module %q go %q toolchain %q", gm.ModuleName, gm.GoVersion, gm.Toolchain)
+	}
+
+	var requires []string
+	for _, r := range gm.Requires {
+		requires = append(requires, fmt.Sprintf("%s %s indirect=%v line=%d", r.Path, r.Version, r.Indirect, r.Line))
+	}
+	wantRequires := []string{
+		"example.com/lib v1.2.0 indirect=false line=10",
+		"example.com/util v0.3.0 indirect=true line=11",
+		"example.com/fork v1.0.0 indirect=false line=14",
+	}
+	if !slices.Equal(requires, wantRequires) {
+		t.Errorf("Requires = %q, want %q", requires, wantRequires)
+	}
+	if want := []string{"example.com/lib", "example.com/util", "example.com/fork"}; !slices.Equal(gm.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", gm.Dependencies, want)
+	}
+
+	var replaces []string
+	for _, r := range gm.Replaces {
+		s := fmt.Sprintf("%s %s: %s", r.OldPath, r.OldVersion, r.Kind)
+		if r.IsLocal() {
+			s += fmt.Sprintf(" %s exists=%v", strings.TrimPrefix(r.LocalDir, dir), r.DirExists)
+		}
+		replaces = append(replaces, s)
+	}
+	wantReplaces := []string{
+		"example.com/lib : local /lib exists=true",
+		"example.com/gone : local /app/missing exists=false",
+		"example.com/util v0.3.0: pin",
+		"example.com/fork : fork",
+	}
+	if !slices.Equal(replaces, wantReplaces) || gm.ReplaceCount != 4 {
+		t.Errorf("Replaces = %q (count %d), want %q", replaces, gm.ReplaceCount, wantReplaces)
+	}
+	if n, m := len(gm.LocalReplaces()), len(gm.MissingReplaces()); n != 2 || m != 1 {
+		t.Errorf("LocalReplaces() = %d, MissingReplaces() = %d, want 2 and 1", n, m)
+	}
+
+	if want := []ModuleVersion{{Path: "example.com/util", Version: "v0.2.0"}}; !slices.Equal(gm.Excludes, want) {
+		t.Errorf("Excludes = %v, want %v", gm.Excludes, want)
+	}
+	wantRetracts := []Retract{{Low: "v1.0.0", High: "v1.0.0", Rationale: "published by mistake"}, {Low: "v0.9.0", High: "v0.9.5"}}
+	if !slices.Equal(gm.Retracts, wantRetracts) {
+		t.Errorf("Retracts = %v, want %v", gm.Retracts, wantRetracts)
+	}
+	if want := []string{"example.com/lib/cmd/gen"}; !slices.Equal(gm.Tools, want) {
+		t.Errorf("Tools = %v, want %v", gm.Tools, want)
+	}
+	if want := []Godebug{{Key: "default", Value: "go1.21"}}; !slices.Equal(gm.Godebugs, want) {
+		t.Errorf("Godebugs = %v, want %v", gm.Godebugs, want)
+	}
+
+	if req, ok := gm.Require("example.com/util"); !ok || req.Version != "v0.3.0" {
+		t.Errorf("Require(example.com/util) = %v, %v", req, ok)
+	}
+	if _, ok := gm.Require("example.com/none"); ok {
+		t.Error("Require(example.com/none) found a requirement")
+	}
+}
+
+func TestAnalyzeGoModLax(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.24\n\nrequire example.com/lib v1.2.0\n\nreplace example.com/lib => bad version here\n")
+
+	gm, err := analyzeGoMod(filepath.Join(dir, "go.mod"))
+	if err == nil {
+		t.Fatal("analyzeGoMod() returned no error for an invalid go.mod")
+	}
+	if gm.ModuleName != "example.com/app" || len(gm.Requires) != 1 {
+		t.Errorf("lax result: module %q with %d requires, want example.com/app with 1", gm.ModuleName, len(gm.Requires))
+	}
+
+	if _, err := analyzeGoMod(filepath.Join(dir, "missing", "go.mod")); err == nil {
+		t.Error("analyzeGoMod() returned no error for a missing file")
+	}
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"go/version"
+	"path"
+	"sort"
+)
+
+// GoModVersion is the go and toolchain directives of a single go.mod file.
+type GoModVersion struct {
+	Repo      RepoResult
+	GoModPath string // Path to go.mod relative to the repo root
+	Module    string // Module path
+	GoVersion string // Version from the go directive ("" if missing)
+	Toolchain string // Toolchain directive ("" if missing)
+}
+
+// Name returns the repo name, followed by the module directory for nested
+// modules (e.g., "kit/tools").
+func (v GoModVersion) Name() string {
+	return path.Join(v.Repo.Name, path.Dir(v.GoModPath))
+}
+
+// GoVersionGroup is the go.mod files sharing a go directive version.
+type GoVersionGroup struct {
+	GoVersion string         // Version from the go directive ("" if missing)
+	Modules   []GoModVersion // Sorted by name
+}
+
+// GoModVersions returns the go and toolchain directives of every analyzed
+// go.mod (root and nested), sorted by name.
+func GoModVersions(results []RepoResult) []GoModVersion {
+	var versions []GoModVersion
+	for _, r := range results {
+		for _, gm := range r.GoMods() {
+			versions = append(versions, GoModVersion{
+				Repo:      r,
+				GoModPath: gm.Path,
+				Module:    gm.ModuleName,
+				GoVersion: gm.GoVersion,
+				Toolchain: gm.Toolchain,
+			})
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Name() < versions[j].Name()
+	})
+	return versions
+}
+
+// GroupByGoVersion groups go.mod files by their go directive, newest version
+// first. Files without a go directive are grouped last.
+func GroupByGoVersion(versions []GoModVersion) []GoVersionGroup {
+	byVersion := make(map[string][]GoModVersion)
+	for _, v := range versions {
+		byVersion[v.GoVersion] = append(byVersion[v.GoVersion], v)
+	}
+
+	groups := make([]GoVersionGroup, 0, len(byVersion))
+	for goVersion, modules := range byVersion {
+		groups = append(groups, GoVersionGroup{GoVersion: goVersion, Modules: modules})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].GoVersion, groups[j].GoVersion
+		if a == "" || b == "" {
+			return a != "" // Missing go directives last
+		}
+		return version.Compare("go"+a, "go"+b) > 0
+	})
+	return groups
+}
+
+// GoVersionBelow returns true if the go directive version is lower than
+// minVersion (both without the "go" prefix, e.g. "1.22"). A missing go
+// directive (Go 1.16 semantics) is always below.
+func GoVersionBelow(goVersion, minVersion string) bool {
+	return goVersion == "" || version.Compare("go"+goVersion, "go"+minVersion) < 0
+}
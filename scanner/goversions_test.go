@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGroupByGoVersion(t *testing.T) {
+	goMod := func(path, goVersion, toolchain string) GoModResult {
+		return GoModResult{Path: path, ModuleName: "example.com/" + path, GoVersion: goVersion, Toolchain: toolchain}
+	}
+	results := []RepoResult{
+		{Name: "kit", GoMod: &GoModResult{Path: "go.mod", GoVersion: "1.22"}, GoModFiles: []GoModResult{
+			goMod("tools/go.mod", "1.24", "go1.24.2"),
+		}},
+		{Name: "app", GoMod: &GoModResult{Path: "go.mod", GoVersion: "1.9"}},
+		{Name: "old", GoMod: &GoModResult{Path: "go.mod"}},
+		{Name: "web", GoMod: &GoModResult{Path: "go.mod", GoVersion: "1.22"}},
+		{Name: "next", GoMod: &GoModResult{Path: "go.mod", GoVersion: "1.24rc1"}},
+		{Name: "docs"},
+	}
+
+	versions := GoModVersions(results)
+	var names []string
+	for _, v := range versions {
+		names = append(names, v.Name())
+	}
+	if want := []string{"app", "kit", "kit/tools", "next", "old", "web"}; !slices.Equal(names, want) {
+		t.Errorf("GoModVersions() = %v, want %v", names, want)
+	}
+
+	var got []string
+	for _, g := range GroupByGoVersion(versions) {
+		var members []string
+		for _, m := range g.Modules {
+			members = append(members, m.Name())
+		}
+		got = append(got, g.GoVersion+": "+strings.Join(members, ","))
+	}
+	// As in go/version, the 1.24 language version is below its release candidates
+	want := []string{"1.24rc1: next", "1.24: kit/tools", "1.22: kit,web", "1.9: app", ": old"}
+	if !slices.Equal(got, want) {
+		t.Errorf("GroupByGoVersion() = %q, want %q", got, want)
+	}
+}
+
+func TestGoVersionBelow(t *testing.T) {
+	tests := []struct {
+		goVersion, minVersion string
+		want                  bool
+	}{
+		{"1.21", "1.22", true},
+		{"1.22", "1.22", false},
+		{"1.22.0", "1.22", false},
+		{"1.9", "1.22", true},
+		{"1.22rc1", "1.22", false},
+		{"1.22rc1", "1.22.0", true},
+		{"1.23", "1.22.5", false},
+		{"", "1.16", true},
+	}
+	for _, tt := range tests {
+		if got := GoVersionBelow(tt.goVersion, tt.minVersion); got != tt.want {
+			t.Errorf("GoVersionBelow(%q, %q) = %v, want %v", tt.goVersion, tt.minVersion, got, tt.want)
+		}
+	}
+}
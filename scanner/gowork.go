@@ -0,0 +1,336 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// GoWorkResult holds analysis results for a go.work file.
+type GoWorkResult struct {
+	Path      string          // Path to go.work on disk
+	GoVersion string          // Version from the go directive
+	Toolchain string          // Toolchain directive
+	Uses      []WorkUse       // Use directives
+	Replaces  []Replace       // Replace directives
+	HasSum    bool            // A go.work.sum file exists next to go.work
+	Sums      []ModuleVersion // Module versions with checksums in go.work.sum, sorted
+	IsTracked bool            // go.work is committed to git (repo workspaces only)
+}
+
+// WorkUse is a single use directive in a go.work file.
+type WorkUse struct {
+	Path       string // Path as written in go.work (e.g., "./mogo" or "../mogo")
+	Dir        string // Absolute directory
+	ModulePath string // Module name from the directory's go.mod, if readable
+	Repo       string // Scanned repo containing Dir, if any
+}
+
+// Repos returns the distinct scanned repos used by the workspace, sorted by name.
+func (w GoWorkResult) Repos() []string {
+	var repos []string
+	for _, u := range w.Uses {
+		if u.Repo != "" && !slices.Contains(repos, u.Repo) {
+			repos = append(repos, u.Repo)
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// FindGoWork analyzes the go.work file in dir, returning nil if there is none.
+// Repo fields of use directives are not set; see ResolveWorkspace.
+func FindGoWork(dir string) (*GoWorkResult, error) {
+	goWorkPath := filepath.Join(dir, "go.work")
+	if _, err := os.Stat(goWorkPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	work, err := analyzeGoWork(goWorkPath)
+	return &work, err
+}
+
+// analyzeGoWork parses the go.work file at goWorkPath.
+func analyzeGoWork(goWorkPath string) (GoWorkResult, error) {
+	result := GoWorkResult{Path: goWorkPath}
+
+	data, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		return result, err
+	}
+
+	f, err := modfile.ParseWork(goWorkPath, data, nil)
+	if err != nil {
+		return result, err
+	}
+
+	dir := filepath.Dir(goWorkPath)
+	if f.Go != nil {
+		result.GoVersion = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		result.Toolchain = f.Toolchain.Name
+	}
+	for _, u := range f.Use {
+		use := WorkUse{Path: u.Path, Dir: u.Path}
+		if !filepath.IsAbs(use.Dir) {
+			use.Dir = filepath.Join(dir, use.Dir)
+		}
+		use.Dir = filepath.Clean(use.Dir)
+		if goMod, err := analyzeGoMod(filepath.Join(use.Dir, "go.mod")); err == nil {
+			use.ModulePath = goMod.ModuleName
+		}
+		result.Uses = append(result.Uses, use)
+	}
+	for _, r := range f.Replace {
+		result.Replaces = append(result.Replaces, newReplace(r, dir))
+	}
+
+	sums, err := parseSumFile(filepath.Join(dir, "go.work.sum"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return result, err
+	}
+	result.HasSum = err == nil
+	result.Sums = sums
+
+	return result, nil
+}
+
+// parseSumFile returns the module versions listed in a go.sum-format file
+// (e.g., go.work.sum), sorted by path and version. Lines for a module's
+// go.mod ("v1.2.3/go.mod") and its content are reported once.
+func parseSumFile(sumPath string) ([]ModuleVersion, error) {
+	data, err := os.ReadFile(sumPath)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[ModuleVersion]bool)
+	var sums []ModuleVersion
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "h1:") {
+			return nil, fmt.Errorf("%s:%d: malformed line %q", sumPath, i+1, line)
+		}
+		mv := ModuleVersion{Path: fields[0], Version: strings.TrimSuffix(fields[1], "/go.mod")}
+		if !seen[mv] {
+			seen[mv] = true
+			sums = append(sums, mv)
+		}
+	}
+	sort.Slice(sums, func(i, j int) bool {
+		if sums[i].Path != sums[j].Path {
+			return sums[i].Path < sums[j].Path
+		}
+		return semver.Compare(sums[i].Version, sums[j].Version) < 0
+	})
+	return sums, nil
+}
+
+// ResolveWorkspace sets the Repo field of each use directive (and TargetRepo
+// of local-path replaces) to the scanned repo containing its directory.
+func ResolveWorkspace(work *GoWorkResult, results []RepoResult) {
+	for i := range work.Uses {
+		work.Uses[i].Repo = FindRepoForDir(work.Uses[i].Dir, results)
+	}
+	for i := range work.Replaces {
+		if work.Replaces[i].IsLocal() {
+			work.Replaces[i].TargetRepo = FindRepoForDir(work.Replaces[i].LocalDir, results)
+		}
+	}
+}
+
+// WorkspaceGroups returns sets of repos that are developed together in a
+// workspace. Each repo's own go.work links it with the repos it uses, and the
+// scan-root go.work (if non-nil) links all repos it uses. Overlapping
+// workspaces are merged. Only groups with two or more repos are returned,
+// each sorted by name.
+func WorkspaceGroups(results []RepoResult, rootWork *GoWorkResult) [][]string {
+	// Union-find over repo names
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(name string) string {
+		if p, ok := parent[name]; ok && p != name {
+			root := find(p)
+			parent[name] = root
+			return root
+		}
+		parent[name] = name
+		return name
+	}
+	union := func(names []string) {
+		for _, name := range names[1:] {
+			parent[find(name)] = find(names[0])
+		}
+	}
+
+	for _, r := range results {
+		if r.GoWork != nil {
+			union(append([]string{r.Name}, r.GoWork.Repos()...))
+		}
+	}
+	if rootWork != nil {
+		if repos := rootWork.Repos(); len(repos) > 0 {
+			union(repos)
+		}
+	}
+
+	members := make(map[string][]string)
+	for name := range parent {
+		root := find(name)
+		members[root] = append(members[root], name)
+	}
+
+	var groups [][]string
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Strings(group)
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+	return groups
+}
+
+// ModuleDirs returns the directories of the repo's Go modules: the repo root
+// when it has a go.mod, followed by nested module directories (when scanned
+// with Recurse).
+func (r RepoResult) ModuleDirs() []string {
+	var dirs []string
+	for _, gm := range r.GoMods() {
+		dirs = append(dirs, filepath.Join(r.Path, filepath.Dir(gm.Path)))
+	}
+	return dirs
+}
+
+// MaxGoVersion returns the highest go directive version among the given go.mod
+// analyses, or "" if none declare one.
+func MaxGoVersion(goMods []GoModResult) string {
+	maxVersion := ""
+	for _, gm := range goMods {
+		if gm.GoVersion != "" && (maxVersion == "" || version.Compare("go"+gm.GoVersion, "go"+maxVersion) > 0) {
+			maxVersion = gm.GoVersion
+		}
+	}
+	return maxVersion
+}
+
+// NewGoWork returns the contents of a go.work file in workDir that uses each
+// of the module directories. goVersion is omitted when empty.
+func NewGoWork(workDir, goVersion string, dirs []string) ([]byte, error) {
+	f := &modfile.WorkFile{Syntax: &modfile.FileSyntax{}}
+	if goVersion != "" {
+		if err := f.AddGoStmt(goVersion); err != nil {
+			return nil, err
+		}
+	}
+	for _, dir := range dirs {
+		usePath, err := workUsePath(workDir, dir)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.AddUse(usePath, ""); err != nil {
+			return nil, err
+		}
+	}
+	f.SortBlocks()
+	f.Cleanup()
+	return modfile.Format(f.Syntax), nil
+}
+
+// SyncGoWork updates the go.work file at goWorkPath so it uses exactly the
+// given module directories among those it manages: directories in dirs that
+// are missing are added, and uses for which isManaged returns true but that
+// are not in dirs are dropped. Uses outside the managed set, the go and
+// toolchain directives, replaces, and comments are preserved. It returns the
+// original and updated contents and the use paths added and removed.
+func SyncGoWork(goWorkPath string, dirs []string, isManaged func(dir string) bool) (original, updated []byte, added, removed []string, err error) {
+	original, err = os.ReadFile(goWorkPath)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	f, err := modfile.ParseWork(goWorkPath, original, nil)
+	if err != nil {
+		return original, nil, nil, nil, err
+	}
+
+	workDir := filepath.Dir(goWorkPath)
+	wanted := make(map[string]bool)
+	for _, dir := range dirs {
+		wanted[filepath.Clean(dir)] = true
+	}
+
+	// Find managed uses that are no longer selected
+	present := make(map[string]bool)
+	for _, u := range f.Use {
+		dir := u.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workDir, dir)
+		}
+		dir = filepath.Clean(dir)
+		present[dir] = true
+		if !wanted[dir] && isManaged(dir) {
+			removed = append(removed, u.Path)
+		}
+	}
+	for _, usePath := range removed {
+		if err := f.DropUse(usePath); err != nil {
+			return original, nil, nil, nil, err
+		}
+	}
+
+	// Add selected directories that are not used yet
+	for _, dir := range dirs {
+		if present[filepath.Clean(dir)] {
+			continue
+		}
+		usePath, err := workUsePath(workDir, dir)
+		if err != nil {
+			return original, nil, nil, nil, err
+		}
+		if err := f.AddUse(usePath, ""); err != nil {
+			return original, nil, nil, nil, err
+		}
+		added = append(added, usePath)
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return original, original, nil, nil, nil
+	}
+
+	f.SortBlocks()
+	f.Cleanup()
+	return original, modfile.Format(f.Syntax), added, removed, nil
+}
+
+// workUsePath returns dir relative to workDir in the form written by
+// `go work use` (e.g., "./mogo" or "../mogo").
+func workUsePath(workDir, dir string) (string, error) {
+	rel, err := filepath.Rel(workDir, dir)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel != "." && rel != ".." && !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel, nil
+}
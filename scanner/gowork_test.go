@@ -0,0 +1,145 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to dir/name, creating parent directories.
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnalyzeGoWork(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.work", "go 1.24\n\nuse (\n\t./mogo\n\t./goauth\n)\n")
+	writeFile(t, dir, "mogo/go.mod", "module github.com/grokify/mogo\n\ngo 1.24\n")
+	writeFile(t, dir, "go.work.sum", strings.Join([]string{
+		"golang.org/x/net v0.20.0 h1:aaa=",
+		"golang.org/x/net v0.20.0/go.mod h1:bbb=",
+		"golang.org/x/net v0.9.0/go.mod h1:ccc=",
+		"",
+		"cloud.google.com/go v0.110.0 h1:ddd=",
+	}, "\n"))
+
+	work, err := FindGoWork(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.GoVersion != "1.24" || len(work.Uses) != 2 {
+		t.Fatalf("FindGoWork() = go %q with %d uses, want go 1.24 with 2 uses", work.GoVersion, len(work.Uses))
+	}
+	if got := work.Uses[0].ModulePath; got != "github.com/grokify/mogo" {
+		t.Errorf("use ./mogo module = %q, want github.com/grokify/mogo", got)
+	}
+	if got := work.Uses[1].ModulePath; got != "" {
+		t.Errorf("use ./goauth module = %q, want none", got)
+	}
+
+	want := []ModuleVersion{
+		{Path: "cloud.google.com/go", Version: "v0.110.0"},
+		{Path: "golang.org/x/net", Version: "v0.9.0"},
+		{Path: "golang.org/x/net", Version: "v0.20.0"},
+	}
+	if !work.HasSum || !slices.Equal(work.Sums, want) {
+		t.Errorf("go.work.sum = %v (present %v), want %v", work.Sums, work.HasSum, want)
+	}
+}
+
+func TestAnalyzeGoWorkSum(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.work", "go 1.24\n")
+
+	work, err := FindGoWork(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.HasSum || len(work.Sums) != 0 {
+		t.Errorf("without go.work.sum: HasSum = %v, Sums = %v", work.HasSum, work.Sums)
+	}
+
+	writeFile(t, dir, "go.work.sum", "golang.org/x/net v0.20.0\n")
+	if _, err := FindGoWork(dir); err == nil || !strings.Contains(err.Error(), "go.work.sum:1: malformed line") {
+		t.Errorf("malformed go.work.sum error = %v", err)
+	}
+}
+
+// workRepo returns a repo with a go.mod for module example.com/<name>
+// requiring the modules of deps, and a go.work using the repos in uses.
+func workRepo(name string, uses []string, deps ...string) RepoResult {
+	r := RepoResult{Name: name, ModuleName: "example.com/" + name}
+	gm := &GoModResult{Path: "go.mod", ModuleName: r.ModuleName}
+	for _, d := range deps {
+		gm.Requires = append(gm.Requires, Require{Path: "example.com/" + d, Version: "v1.0.0"})
+		gm.Dependencies = append(gm.Dependencies, "example.com/"+d)
+	}
+	r.GoMod, r.Dependencies = gm, gm.Dependencies
+	if uses != nil {
+		r.GoWork = &GoWorkResult{}
+		for _, u := range uses {
+			r.GoWork.Uses = append(r.GoWork.Uses, WorkUse{Path: "../" + u, Repo: u})
+		}
+	}
+	return r
+}
+
+func TestWorkspaceGroups(t *testing.T) {
+	results := []RepoResult{
+		workRepo("a", []string{"b"}),
+		workRepo("b", nil),
+		workRepo("c", []string{"d"}),
+		workRepo("e", []string{"c"}),
+		workRepo("f", nil),
+	}
+	rootWork := &GoWorkResult{Uses: []WorkUse{{Repo: "f"}, {Repo: "g"}}}
+
+	var got []string
+	for _, g := range WorkspaceGroups(results, rootWork) {
+		got = append(got, strings.Join(g, "+"))
+	}
+	want := []string{"a+b", "c+d+e", "f+g"}
+	if !slices.Equal(got, want) {
+		t.Errorf("WorkspaceGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestTopologicalSortUnits(t *testing.T) {
+	all := []RepoResult{
+		workRepo("lib", []string{"util", "tool"}),
+		workRepo("util", nil),
+		workRepo("tool", nil),
+		workRepo("app", nil, "lib"),
+	}
+	groups := WorkspaceGroups(all, nil)
+
+	unitNames := func(results []RepoResult) []string {
+		units, cycles := TopologicalSortUnits(results, groups)
+		if len(cycles) > 0 {
+			t.Fatalf("unexpected cycles: %v", cycles)
+		}
+		var names []string
+		for _, u := range units {
+			names = append(names, u.Name)
+		}
+		return names
+	}
+
+	if got, want := unitNames(all), []string{"lib+tool+util", "app"}; !slices.Equal(got, want) {
+		t.Errorf("units = %v, want %v", got, want)
+	}
+	// Filtered-out members must not appear in unit names
+	selected := []RepoResult{all[0], all[1], all[3]}
+	if got, want := unitNames(selected), []string{"lib+util", "app"}; !slices.Equal(got, want) {
+		t.Errorf("units of selected repos = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"slices"
+	"sort"
+)
+
+// Dependent is a repo that transitively depends on an impact target.
+type Dependent struct {
+	Repo      RepoResult
+	Depth     int      // 1 for direct dependents of the target
+	Path      []string // Target and repos through which this repo is first reached, ending with the repo
+	DependsOn []string // Direct dependencies among the target and its other dependents
+}
+
+// Impact is the set of managed repos affected by a change to a repo or module.
+type Impact struct {
+	Target     string      // Repo name or module path as given
+	Repo       string      // Scanned repo owning the target module ("" for external modules)
+	Module     string      // Module path of the target ("" for repos without a go.mod)
+	Dependents []Dependent // Sorted by depth, then name
+}
+
+// MaxDepth returns the greatest depth among the dependents.
+func (im Impact) MaxDepth() int {
+	maxDepth := 0
+	for _, d := range im.Dependents {
+		maxDepth = max(maxDepth, d.Depth)
+	}
+	return maxDepth
+}
+
+// GetImpact returns the repos that transitively depend on target, which is a
+// scanned repo name or a module path. A module owned by a scanned repo
+// (including nested modules) resolves to that repo. For other modules (e.g.,
+// an external library), the repos requiring it directly are at depth 1.
+// Dependents are found breadth-first as in GetTransitiveDependents, so each
+// path is a shortest path from the target. Returns false if target is neither
+// a scanned repo nor a module required by one.
+func GetImpact(target string, allResults []RepoResult) (Impact, bool) {
+	im := Impact{Target: target}
+	modules := ModuleNodes(allResults)
+
+	byName := make(map[string]RepoResult)
+	for _, r := range allResults {
+		byName[r.Name] = r
+	}
+
+	// Resolve the target to a repo, or to the repos requiring an external module
+	root := target
+	var seeds []string
+	if r, ok := byName[target]; ok {
+		im.Repo = r.Name
+		im.Module = r.ModuleName
+		seeds = []string{r.Name}
+	} else if node, ok := modules[target]; ok {
+		im.Repo = node.Repo
+		im.Module = node.Path
+		root = node.Repo
+		seeds = []string{node.Repo}
+	} else {
+		im.Module = target
+		for _, r := range allResults {
+			if r.HasDependency(target) {
+				seeds = append(seeds, r.Name)
+			}
+		}
+		if len(seeds) == 0 {
+			return im, false
+		}
+	}
+
+	paths := make(map[string][]string)
+	if im.Repo != "" {
+		paths[im.Repo] = []string{root}
+	} else {
+		for _, s := range seeds {
+			paths[s] = []string{root, s}
+		}
+	}
+	walkDependents(seeds, reverseDeps(allResults), func(repo, from string) {
+		paths[repo] = append(slices.Clone(paths[from]), repo)
+	})
+
+	for name, p := range paths {
+		if name == im.Repo {
+			continue
+		}
+		im.Dependents = append(im.Dependents, Dependent{
+			Repo:  byName[name],
+			Depth: len(p) - 1,
+			Path:  p,
+		})
+	}
+
+	// Record which affected repos each dependent requires directly
+	for i := range im.Dependents {
+		d := &im.Dependents[i]
+		for _, dep := range internalDeps(d.Repo, modules) {
+			if _, affected := paths[dep]; affected {
+				d.DependsOn = append(d.DependsOn, dep)
+			}
+		}
+		if im.Repo == "" && d.Repo.HasDependency(target) {
+			d.DependsOn = append([]string{target}, d.DependsOn...)
+		}
+	}
+
+	sort.Slice(im.Dependents, func(i, j int) bool {
+		a, b := im.Dependents[i], im.Dependents[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		return a.Repo.Name < b.Repo.Name
+	})
+	return im, true
+}
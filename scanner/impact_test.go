@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGetImpact(t *testing.T) {
+	results := []RepoResult{
+		workRepo("base", nil),
+		workRepo("left", nil, "base"),
+		workRepo("right", nil, "base"),
+		workRepo("top", nil, "left", "right"),
+		workRepo("app", nil, "top", "base"),
+		workRepo("solo", nil),
+		workRepo("loop", nil, "app"),
+	}
+	require := func(r *RepoResult, gm *GoModResult, paths ...string) {
+		for _, p := range paths {
+			gm.Requires = append(gm.Requires, Require{Path: p, Version: "v1.0.0"})
+			gm.Dependencies = append(gm.Dependencies, p)
+		}
+		r.Dependencies = r.GoMod.Dependencies
+	}
+	// base also depends on loop, closing a cycle back to the target
+	require(&results[0], results[0].GoMod, "example.com/loop")
+	// kit has a nested module required by solo, and requires an external module
+	kit := workRepo("kit", nil)
+	kit.GoModFiles = []GoModResult{{Path: "tools/go.mod", ModuleName: "example.com/kit/tools"}}
+	require(&kit, kit.GoMod, "example.com/ext")
+	require(&results[5], results[5].GoMod, "example.com/kit/tools")
+	results = append(results, kit)
+
+	// format returns "depth name path [dependsOn]" per dependent
+	format := func(im Impact) []string {
+		var got []string
+		for _, d := range im.Dependents {
+			got = append(got, fmt.Sprintf("%d %s %s [%s]", d.Depth, d.Repo.Name, strings.Join(d.Path, ">"), strings.Join(d.DependsOn, ",")))
+		}
+		return got
+	}
+
+	tests := []struct {
+		target   string
+		repo     string
+		module   string
+		want     []string
+		maxDepth int
+	}{
+		{
+			"base", "base", "example.com/base",
+			[]string{
+				"1 app base>app [base,top]",
+				"1 left base>left [base]",
+				"1 right base>right [base]",
+				"2 loop base>app>loop [app]",
+				"2 top base>left>top [left,right]",
+			},
+			2,
+		},
+		{
+			// Through the cycle, top is reached again but not listed as its own dependent
+			"top", "top", "example.com/top",
+			[]string{
+				"1 app top>app [base,top]",
+				"2 loop top>app>loop [app]",
+				"3 base top>app>loop>base [loop]",
+				"4 left top>app>loop>base>left [base]",
+				"4 right top>app>loop>base>right [base]",
+			},
+			4,
+		},
+		{"example.com/kit/tools", "kit", "example.com/kit/tools", []string{"1 solo kit>solo [kit]"}, 1},
+		{"example.com/ext", "", "example.com/ext", []string{"1 kit example.com/ext>kit [example.com/ext]", "2 solo example.com/ext>kit>solo [kit]"}, 2},
+		{"solo", "solo", "example.com/solo", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			im, ok := GetImpact(tt.target, results)
+			if !ok {
+				t.Fatal("GetImpact() did not resolve the target")
+			}
+			if im.Repo != tt.repo || im.Module != tt.module {
+				t.Errorf("GetImpact() repo %q module %q, want %q %q", im.Repo, im.Module, tt.repo, tt.module)
+			}
+			if got := format(im); !slices.Equal(got, tt.want) {
+				t.Errorf("GetImpact() dependents =\n%q\nwant\n%q", got, tt.want)
+			}
+			if im.MaxDepth() != tt.maxDepth {
+				t.Errorf("MaxDepth() = %d, want %d", im.MaxDepth(), tt.maxDepth)
+			}
+		})
+	}
+
+	if _, ok := GetImpact("example.com/unknown", results); ok {
+		t.Error("GetImpact() resolved a module nothing requires")
+	}
+}
@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// VersionBump is the semver component to increment for a release.
+type VersionBump int
+
+const (
+	BumpNone  VersionBump = iota // Nothing to release
+	BumpPatch                    // Fixes and other changes
+	BumpMinor                    // New features
+	BumpMajor                    // Breaking changes
+)
+
+// String returns the lowercase name of the bump.
+func (b VersionBump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// conventionalHeader matches a conventional commit header such as
+// "feat(api)!: add endpoint", capturing the type and breaking marker.
+var conventionalHeader = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?: `)
+
+// CommitBump classifies a commit message following Conventional Commits:
+// a "!" after the type or a BREAKING CHANGE footer is a major change, "feat"
+// is a minor change, and anything else (including non-conventional messages)
+// is a patch change.
+func CommitBump(message string) VersionBump {
+	header, body, _ := strings.Cut(message, "\n")
+	for line := range strings.SplitSeq(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			return BumpMajor
+		}
+	}
+
+	m := conventionalHeader.FindStringSubmatch(strings.TrimSpace(header))
+	switch {
+	case m == nil:
+		return BumpPatch
+	case m[2] == "!":
+		return BumpMajor
+	case strings.EqualFold(m[1], "feat"):
+		return BumpMinor
+	default:
+		return BumpPatch
+	}
+}
+
+// NextVersion returns the version after current for the given bump. Before
+// v1, breaking changes bump the minor version. An empty current version
+// yields the first version for the module path's major version (v0.1.0, or
+// e.g. v2.0.0 for a /v2 module). Pre-release versions are released as their
+// core version when the bump does not exceed what the pre-release implies.
+func NextVersion(current string, bump VersionBump, modulePath string) string {
+	if current == "" {
+		if _, pathMajor, ok := module.SplitPathVersion(modulePath); ok && pathMajor != "" {
+			return module.PathMajorPrefix(pathMajor) + ".0.0"
+		}
+		return "v0.1.0"
+	}
+	if bump == BumpNone {
+		return current
+	}
+
+	var major, minor, patch int
+	core := strings.TrimSuffix(semver.Canonical(current), semver.Prerelease(current))
+	if _, err := fmt.Sscanf(core, "v%d.%d.%d", &major, &minor, &patch); err != nil {
+		return ""
+	}
+
+	if major == 0 && bump == BumpMajor {
+		bump = BumpMinor
+	}
+	if semver.Prerelease(current) != "" {
+		// v1.3.0-rc.1 releases as v1.3.0 unless the changes need a larger bump
+		switch {
+		case patch > 0:
+			if bump == BumpPatch {
+				return core
+			}
+		case minor > 0:
+			if bump != BumpMajor {
+				return core
+			}
+		default:
+			return core // vN.0.0 pre-releases already imply a major release
+		}
+	}
+
+	switch bump {
+	case BumpMajor:
+		return fmt.Sprintf("v%d.0.0", major+1)
+	case BumpMinor:
+		return fmt.Sprintf("v%d.%d.0", major, minor+1)
+	default:
+		return fmt.Sprintf("v%d.%d.%d", major, minor, patch+1)
+	}
+}
+
+// ReleasePlan is the suggested next release of a repo's root module.
+type ReleasePlan struct {
+	Repo         RepoResult
+	Current      string      // Latest semver tag ("" if never released)
+	Commits      int         // Commits since Current
+	Breaking     int         // Commits with breaking changes
+	Features     int         // Feature commits
+	Other        int         // Other commits (fixes, chores, non-conventional messages)
+	Bump         VersionBump // Bump implied by the commits (NextVersion releases breaking changes before v1 as minor)
+	Next         string      // Suggested next version
+	DependsOn    []string    // Dependencies also being released (dependency-only releases)
+	NeedsNewPath bool        // The next major version (v2+) requires a new /vN module path
+}
+
+// Reason returns a short explanation of the suggested bump,
+// e.g. "1 breaking, 2 feat, 3 other" or "dependency update: mogo".
+func (p ReleasePlan) Reason() string {
+	switch {
+	case p.Current == "":
+		return "initial release"
+	case p.Commits == 0 && len(p.DependsOn) > 0:
+		return "dependency update: " + strings.Join(p.DependsOn, ", ")
+	}
+	var parts []string
+	if p.Breaking > 0 {
+		parts = append(parts, fmt.Sprintf("%d breaking", p.Breaking))
+	}
+	if p.Features > 0 {
+		parts = append(parts, fmt.Sprintf("%d feat", p.Features))
+	}
+	if p.Other > 0 {
+		parts = append(parts, fmt.Sprintf("%d other", p.Other))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PlanRelease suggests the next release of the repo's root module from the
+// conventional commit messages since its latest semver tag. Tags must have
+// been collected during the scan (ScanOptions.CheckTags). dependsOn lists
+// dependencies that are being released; a repo without commits of its own
+// needs a patch release to pick them up. Returns a plan with BumpNone if
+// there is nothing to release.
+func PlanRelease(r RepoResult, backend GitBackend, dependsOn []string) (ReleasePlan, error) {
+	plan := ReleasePlan{Repo: r, Current: r.LatestTag}
+
+	ref := ""
+	if r.LatestTag != "" {
+		ref = "refs/tags/" + r.LatestTag
+	}
+	messages, err := backend.CommitMessages(r.Path, ref)
+	if err != nil {
+		return plan, err
+	}
+
+	plan.Commits = len(messages)
+	for _, msg := range messages {
+		bump := CommitBump(msg)
+		switch bump {
+		case BumpMajor:
+			plan.Breaking++
+		case BumpMinor:
+			plan.Features++
+		default:
+			plan.Other++
+		}
+		plan.Bump = max(plan.Bump, bump)
+	}
+	if plan.Bump == BumpNone && len(dependsOn) > 0 {
+		plan.Bump = BumpPatch
+		plan.DependsOn = dependsOn
+	}
+	if plan.Current == "" && plan.Commits > 0 {
+		plan.Bump = max(plan.Bump, BumpMinor)
+	}
+	if plan.Bump == BumpNone {
+		plan.Next = plan.Current
+		return plan, nil
+	}
+
+	plan.Next = NextVersion(plan.Current, plan.Bump, r.ModuleName)
+	if major := semver.Major(plan.Next); plan.Current != "" && major != semver.Major(plan.Current) && major != "v1" {
+		plan.NeedsNewPath = true
+	}
+	return plan, nil
+}
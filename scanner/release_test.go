@@ -0,0 +1,107 @@
+package scanner
+
+import "testing"
+
+func TestCommitBump(t *testing.T) {
+	tests := []struct {
+		message string
+		want    VersionBump
+	}{
+		{"fix: handle empty input", BumpPatch},
+		{"feat: add endpoint", BumpMinor},
+		{"Feat: add endpoint", BumpMinor},
+		{"feat(api): add endpoint", BumpMinor},
+		{"feat!: drop v1 endpoint", BumpMajor},
+		{"fix(api)!: change error type", BumpMajor},
+		{"refactor!: rename package", BumpMajor},
+		{"chore: update deps", BumpPatch},
+		{"Update README", BumpPatch},
+		{"feat:missing space", BumpPatch},
+		{"feat: new option\n\nBREAKING CHANGE: Config is now required", BumpMajor},
+		{"fix: tidy\n\nBREAKING-CHANGE: removed Foo", BumpMajor},
+		{"docs: mention BREAKING CHANGE: in header", BumpPatch},
+		{"fix: typo\n\nNot a BREAKING CHANGE: footer", BumpPatch},
+	}
+	for _, tt := range tests {
+		if got := CommitBump(tt.message); got != tt.want {
+			t.Errorf("CommitBump(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		current    string
+		bump       VersionBump
+		modulePath string
+		want       string
+	}{
+		{"v1.2.3", BumpPatch, "", "v1.2.4"},
+		{"v1.2.3", BumpMinor, "", "v1.3.0"},
+		{"v1.2.3", BumpMajor, "", "v2.0.0"},
+		{"v1.2.3", BumpNone, "", "v1.2.3"},
+		{"v0.4.2", BumpMajor, "", "v0.5.0"}, // Before v1, breaking changes bump the minor version
+		{"v0.4.2", BumpMinor, "", "v0.5.0"},
+		{"v0.4.2", BumpPatch, "", "v0.4.3"},
+		{"", BumpMinor, "example.com/mod", "v0.1.0"},
+		{"", BumpMajor, "example.com/mod/v2", "v2.0.0"},
+		{"", BumpMinor, "gopkg.in/yaml.v3", "v3.0.0"},
+		{"v1.3.0-rc.1", BumpPatch, "", "v1.3.0"},
+		{"v1.3.0-rc.1", BumpMinor, "", "v1.3.0"},
+		{"v1.3.0-rc.1", BumpMajor, "", "v2.0.0"},
+		{"v1.2.4-rc.1", BumpPatch, "", "v1.2.4"},
+		{"v1.2.4-rc.1", BumpMinor, "", "v1.3.0"},
+		{"v1.2.4-rc.1", BumpMajor, "", "v2.0.0"},
+		{"v0.2.0-rc.1", BumpMajor, "", "v0.2.0"}, // Breaking changes before v1 bump the minor version
+		{"v2.0.0-beta.1", BumpMajor, "", "v2.0.0"},
+	}
+	for _, tt := range tests {
+		if got := NextVersion(tt.current, tt.bump, tt.modulePath); got != tt.want {
+			t.Errorf("NextVersion(%q, %s, %q) = %q, want %q", tt.current, tt.bump, tt.modulePath, got, tt.want)
+		}
+	}
+}
+
+// commitsBackend is a GitBackend returning fixed commit messages.
+type commitsBackend struct {
+	GitBackend
+	messages []string
+}
+
+func (b commitsBackend) CommitMessages(repoPath, ref string) ([]string, error) {
+	return b.messages, nil
+}
+
+func TestPlanRelease(t *testing.T) {
+	tests := []struct {
+		name      string
+		latestTag string
+		module    string
+		messages  []string
+		dependsOn []string
+		wantBump  VersionBump
+		wantNext  string
+		wantPath  bool
+	}{
+		{"nothing to release", "v1.0.0", "example.com/mod", nil, nil, BumpNone, "v1.0.0", false},
+		{"fixes", "v1.0.0", "example.com/mod", []string{"fix: a", "chore: b"}, nil, BumpPatch, "v1.0.1", false},
+		{"feature", "v1.0.0", "example.com/mod", []string{"fix: a", "feat: b"}, nil, BumpMinor, "v1.1.0", false},
+		{"breaking v1", "v1.0.0", "example.com/mod", []string{"feat!: b"}, nil, BumpMajor, "v2.0.0", true},
+		{"breaking v0", "v0.3.0", "example.com/mod", []string{"feat!: b"}, nil, BumpMajor, "v0.4.0", false},
+		{"dependency update", "v1.0.0", "example.com/mod", nil, []string{"lib"}, BumpPatch, "v1.0.1", false},
+		{"initial release", "", "example.com/mod", []string{"fix: a"}, nil, BumpMinor, "v0.1.0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := RepoResult{Name: "mod", ModuleName: tt.module, LatestTag: tt.latestTag}
+			plan, err := PlanRelease(r, commitsBackend{messages: tt.messages}, tt.dependsOn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.Bump != tt.wantBump || plan.Next != tt.wantNext || plan.NeedsNewPath != tt.wantPath {
+				t.Errorf("PlanRelease() = %s %s (new path %v), want %s %s (new path %v)",
+					plan.Bump, plan.Next, plan.NeedsNewPath, tt.wantBump, tt.wantNext, tt.wantPath)
+			}
+		})
+	}
+}
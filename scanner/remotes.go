@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// NormalizeRemoteURL reduces a git remote URL to host and path without a
+// scheme, user, port or .git suffix, so that SSH and HTTPS forms of the same
+// repository compare equal:
+//
+//	git@github.com:grokify/mogo.git         -> github.com/grokify/mogo
+//	ssh://git@github.com:22/grokify/mogo    -> github.com/grokify/mogo
+//	https://github.com/grokify/mogo.git     -> github.com/grokify/mogo
+//
+// Local remotes (file:// URLs and paths) are reduced to their path.
+func NormalizeRemoteURL(rawURL string) string {
+	s := strings.TrimSpace(rawURL)
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return s
+		}
+		if u.Scheme == "file" {
+			s = u.Path
+		} else {
+			s = strings.ToLower(u.Hostname()) + u.Path
+		}
+	} else if i := strings.Index(s, ":"); i > 0 && !strings.Contains(s[:i], "/") {
+		// scp-like syntax: [user@]host:path
+		host := s[:i]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		s = strings.ToLower(host) + "/" + strings.TrimPrefix(s[i+1:], "/")
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+}
+
+// Origin returns the first URL of the remote named origin, or "" if there is
+// none. Requires the scan to collect remotes (ScanOptions.CheckRemotes).
+func (r RepoResult) Origin() string {
+	for _, remote := range r.Remotes {
+		if remote.Name == "origin" && len(remote.URLs) > 0 {
+			return remote.URLs[0]
+		}
+	}
+	return ""
+}
+
+// RemoteStatus is the consistency of a repo's remotes with its module path
+// and with the other scanned repos.
+type RemoteStatus struct {
+	Repo       RepoResult
+	Origin     string   // Normalized origin URL ("" if there is no origin)
+	ModulePath string   // Root module path without its major version suffix ("" without a go.mod)
+	Mismatch   bool     // ModulePath differs from Origin
+	NoRemote   bool     // No remotes are configured
+	NoOrigin   bool     // Remotes are configured, but none is named origin
+	Duplicates []string // Other repos with the same Origin, sorted
+}
+
+// HasIssues returns true if any remote check failed.
+func (s RemoteStatus) HasIssues() bool {
+	return s.Mismatch || s.NoRemote || s.NoOrigin || len(s.Duplicates) > 0
+}
+
+// RemoteStatuses checks the remotes of each git repo: whether the root module
+// path (ignoring a /vN major version suffix) matches the normalized origin
+// URL, whether any remote (or origin) is configured, and whether several
+// repos are clones of the same origin. Requires the scan to collect remotes
+// (ScanOptions.CheckRemotes). Results are in the order of results.
+func RemoteStatuses(results []RepoResult) []RemoteStatus {
+	var statuses []RemoteStatus
+	byOrigin := make(map[string][]string)
+	for _, r := range results {
+		if !r.IsGitRepo {
+			continue
+		}
+		s := RemoteStatus{
+			Repo:     r,
+			Origin:   NormalizeRemoteURL(r.Origin()),
+			NoRemote: len(r.Remotes) == 0,
+		}
+		s.NoOrigin = !s.NoRemote && s.Origin == ""
+
+		if r.ModuleName != "" {
+			s.ModulePath = r.ModuleName
+			if prefix, _, ok := module.SplitPathVersion(r.ModuleName); ok {
+				s.ModulePath = prefix
+			}
+			s.Mismatch = s.Origin != "" && !strings.EqualFold(s.ModulePath, s.Origin)
+		}
+		if s.Origin != "" {
+			key := strings.ToLower(s.Origin)
+			byOrigin[key] = append(byOrigin[key], r.Name)
+		}
+		statuses = append(statuses, s)
+	}
+
+	for i := range statuses {
+		s := &statuses[i]
+		if s.Origin == "" {
+			continue
+		}
+		for _, name := range byOrigin[strings.ToLower(s.Origin)] {
+			if name != s.Repo.Name {
+				s.Duplicates = append(s.Duplicates, name)
+			}
+		}
+		sort.Strings(s.Duplicates)
+	}
+	return statuses
+}
@@ -1,23 +1,20 @@
 package scanner
 
 import (
-	"bufio"
+	"errors"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
-)
 
-// GoModResult holds analysis results for a single go.mod file.
-type GoModResult struct {
-	Path         string   // Path to go.mod relative to repo root
-	ModuleName   string   // Module name from go.mod
-	Dependencies []string // Required module paths
-	ReplaceCount int      // Number of replace directives
-}
+	"golang.org/x/mod/module"
+)
 
 // RepoResult holds the analysis results for a single repository.
 type RepoResult struct {
@@ -30,10 +27,26 @@ type RepoResult struct {
 	HasReplaceDirectives  bool
 	HasModuleMismatch     bool
 	ModuleName            string
-	ReplaceCount          int
-	Dependencies          []string      // Dependencies from root go.mod
-	GoModFiles            []GoModResult // All go.mod files (when recurse=true)
-	LatestModTime         time.Time     // Most recent file modification time
+	ExpectedModulePath    string         // Module path implied by the repo's location (with a go.mod)
+	ReplaceCount          int            // Replace directives in all analyzed go.mod files
+	Dependencies          []string       // Dependencies from root go.mod
+	GoMod                 *GoModResult   // Root go.mod analysis (nil when there is no go.mod)
+	GoModFiles            []GoModResult  // All go.mod files (when recurse=true)
+	GoWork                *GoWorkResult  // Root go.work analysis (nil when there is no go.work)
+	LatestModTime         time.Time      // Most recent file modification time
+	Tags                  []string       // All git tags (when CheckTags=true)
+	LatestTag             string         // Latest semver tag for the root module (when CheckTags=true)
+	CommitsSinceTag       int            // Commits on HEAD since LatestTag (when CheckTags=true)
+	HeadTags              []string       // Tags pointing at HEAD (when CheckTags=true)
+	RemoteChanges         []RefChange    // Remote-tracking branches moved by fetching (when Fetch=true)
+	Upstream              UpstreamStatus // Current branch compared with its upstream (when CheckUpstream=true)
+	Remotes               []Remote       // Configured remotes (when CheckRemotes=true)
+	Errors                []error        // Errors encountered while analyzing the repo
+}
+
+// HasErrors returns true if any errors were encountered while analyzing the repo.
+func (r RepoResult) HasErrors() bool {
+	return len(r.Errors) > 0
 }
 
 // HasDependency checks if the repo depends on the given module path.
@@ -52,6 +65,39 @@ func (r RepoResult) HasDependency(modulePath string) bool {
 	return false
 }
 
+// GoMods returns the root go.mod analysis followed by any nested go.mod files.
+func (r RepoResult) GoMods() []GoModResult {
+	var goMods []GoModResult
+	if r.GoMod != nil {
+		goMods = append(goMods, *r.GoMod)
+	}
+	return append(goMods, r.GoModFiles...)
+}
+
+// LocalReplaces returns local-path replace directives from all analyzed go.mod files.
+func (r RepoResult) LocalReplaces() []Replace {
+	var local []Replace
+	for _, gm := range r.GoMods() {
+		local = append(local, gm.LocalReplaces()...)
+	}
+	return local
+}
+
+// MissingReplaces returns local-path replace directives from all analyzed
+// go.mod files whose target directory does not exist.
+func (r RepoResult) MissingReplaces() []Replace {
+	var missing []Replace
+	for _, gm := range r.GoMods() {
+		missing = append(missing, gm.MissingReplaces()...)
+	}
+	return missing
+}
+
+// HasCommittedGoWork returns true if the repo has a go.work file committed to git.
+func (r RepoResult) HasCommittedGoWork() bool {
+	return r.GoWork != nil && r.GoWork.IsTracked
+}
+
 // ModifiedSince returns true if the repo has files modified within the given duration.
 func (r RepoResult) ModifiedSince(d time.Duration) bool {
 	if r.LatestModTime.IsZero() {
@@ -66,16 +112,55 @@ func (r RepoResult) NeedsPush() bool {
 	return r.HasUncommittedChanges || r.HasUnpushedCommits
 }
 
+// FastForwardBlocker returns why the repo's current branch cannot be safely
+// fast-forwarded to its upstream, or "" if it can (or is already up to date).
+// Requires the scan to compare upstreams (ScanOptions.CheckUpstream).
+func (r RepoResult) FastForwardBlocker() string {
+	u := r.Upstream
+	switch {
+	case !r.IsGitRepo:
+		return "not a git repository"
+	case u.Branch == "":
+		return "detached HEAD"
+	case u.Upstream == "":
+		return fmt.Sprintf("no upstream for branch %s", u.Branch)
+	case u.Gone:
+		return fmt.Sprintf("upstream %s is gone", u.Upstream)
+	case r.HasUncommittedChanges:
+		return "uncommitted changes"
+	case u.Ahead > 0 && u.Behind > 0:
+		return fmt.Sprintf("diverged from %s (ahead %d, behind %d)", u.Upstream, u.Ahead, u.Behind)
+	case u.Ahead > 0:
+		return fmt.Sprintf("%d unpushed commits", u.Ahead)
+	}
+	return ""
+}
+
+// HasUnreleasedCommits returns true if the repo has a go.mod and HEAD has
+// commits since the latest semver tag, or there is no semver tag at all.
+// Requires the scan to collect tags (ScanOptions.CheckTags).
+func (r RepoResult) HasUnreleasedCommits() bool {
+	if !r.IsGitRepo || len(r.GoMods()) == 0 {
+		return false
+	}
+	return r.LatestTag == "" || r.CommitsSinceTag > 0
+}
+
 // ProgressFunc is called during scanning with current progress.
 type ProgressFunc func(current, total int, name string)
 
 // ScanOptions configures the scanning behavior.
 type ScanOptions struct {
-	Recurse       bool       // Search for nested go.mod files
-	CheckModTime  bool       // Compute latest modification time (expensive)
-	CheckUnpushed bool       // Check for unpushed commits
-	Workers       int        // Number of parallel workers (0 = GOMAXPROCS)
-	GitBackend    GitBackend // Git backend to use (nil = default go-git backend)
+	Recurse       bool          // Search for nested go.mod files
+	CheckModTime  bool          // Compute latest modification time (expensive)
+	CheckUnpushed bool          // Check for unpushed commits
+	CheckTags     bool          // Collect git tags and the latest semver tag
+	CheckUpstream bool          // Compare the current branch with its upstream
+	CheckRemotes  bool          // Collect the configured remotes and their URLs
+	Fetch         bool          // Fetch all remotes before checking git status
+	FetchTimeout  time.Duration // Time limit for fetching each repo (0 = DefaultFetchTimeout)
+	Workers       int           // Number of parallel workers (0 = GOMAXPROCS)
+	GitBackend    GitBackend    // Git backend to use (nil = default go-git backend)
 }
 
 // CountDirectories counts the number of scannable directories.
@@ -188,6 +273,13 @@ func ScanDirectoryWithProgress(dirPath string, progressFn ProgressFunc, opts Sca
 		}
 	}
 
+	resolveLocalReplaces(results)
+	for i := range results {
+		if results[i].GoWork != nil {
+			ResolveWorkspace(results[i].GoWork, results)
+		}
+	}
+
 	return results, nil
 }
 
@@ -199,7 +291,11 @@ func analyzeRepo(repoPath, name string, opts ScanOptions) RepoResult {
 
 	// Get latest modification time (only if requested - expensive operation)
 	if opts.CheckModTime {
-		result.LatestModTime = getLatestModTime(repoPath)
+		modTime, err := getLatestModTime(repoPath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("modification time: %w", err))
+		}
+		result.LatestModTime = modTime
 	}
 
 	// Get git backend (default to go-git)
@@ -211,52 +307,136 @@ func analyzeRepo(repoPath, name string, opts ScanOptions) RepoResult {
 	// Check if it's a git repository
 	result.IsGitRepo = backend.IsRepo(repoPath)
 
+	// Fetch first so that unpushed status reflects the remotes
+	if opts.Fetch && result.IsGitRepo {
+		changes, err := FetchRemotes(backend, repoPath, opts.FetchTimeout)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		result.RemoteChanges = changes
+	}
+
 	// Check git status (uncommitted changes and optionally unpushed commits)
 	if result.IsGitRepo {
-		result.HasUncommittedChanges, result.HasUnpushedCommits = backend.GetStatus(repoPath, opts.CheckUnpushed)
+		hasUncommitted, hasUnpushed, err := backend.GetStatus(repoPath, opts.CheckUnpushed)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		result.HasUncommittedChanges, result.HasUnpushedCommits = hasUncommitted, hasUnpushed
+	}
+
+	// Compare the current branch with its upstream
+	if opts.CheckUpstream && result.IsGitRepo {
+		upstream, err := backend.Upstream(repoPath)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		result.Upstream = upstream
+	}
+
+	// Collect remotes
+	if opts.CheckRemotes && result.IsGitRepo {
+		remotes, err := backend.Remotes(repoPath)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		result.Remotes = remotes
 	}
 
 	// Analyze go.mod at root
 	goModPath := filepath.Join(repoPath, "go.mod")
 	if _, err := os.Stat(goModPath); err == nil {
 		result.HasGoMod = true
-		moduleName, replaceCount, dependencies := analyzeGoMod(goModPath)
-		result.ModuleName = moduleName
-		result.ReplaceCount = replaceCount
-		result.HasReplaceDirectives = replaceCount > 0
-		result.Dependencies = dependencies
+		goMod, err := analyzeGoMod(goModPath)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		goMod.Path = "go.mod"
+		result.GoMod = &goMod
+		result.ModuleName = goMod.ModuleName
+		result.ReplaceCount = goMod.ReplaceCount
+		result.HasReplaceDirectives = goMod.ReplaceCount > 0
+		result.Dependencies = goMod.Dependencies
 
 		// Check if module name matches directory structure
-		if moduleName != "" {
-			result.HasModuleMismatch = !moduleMatchesPath(moduleName, name)
+		if goMod.ModuleName != "" {
+			result.ExpectedModulePath = expectedModulePath(goMod.ModuleName, repoPath)
+			result.HasModuleMismatch = goMod.ModuleName != result.ExpectedModulePath
 		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		result.Errors = append(result.Errors, err)
+	}
+
+	// Analyze go.work at root and check whether it is committed
+	goWork, err := FindGoWork(repoPath)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+	if goWork != nil && result.IsGitRepo {
+		tracked, err := backend.IsTracked(repoPath, "go.work")
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		goWork.IsTracked = tracked
+	}
+	result.GoWork = goWork
+
+	// Collect tags and determine the latest release of the root module
+	if opts.CheckTags && result.IsGitRepo {
+		tags, err := backend.ListTags(repoPath)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		result.Tags = tags
+		result.LatestTag = LatestModuleVersion(tags, "", result.ModuleName)
+		if result.LatestTag != "" {
+			n, err := backend.CommitsSince(repoPath, "refs/tags/"+result.LatestTag)
+			if err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+			result.CommitsSinceTag = n
+		}
+		headTags, err := backend.HeadTags(repoPath)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		result.HeadTags = headTags
 	}
 
 	// Find nested go.mod files if recurse is enabled
 	if opts.Recurse {
-		goModFiles := findGoModFiles(repoPath)
+		goModFiles, err := findGoModFiles(repoPath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("find go.mod files: %w", err))
+		}
 		for _, goModFile := range goModFiles {
 			relPath, _ := filepath.Rel(repoPath, goModFile)
-			moduleName, replaceCount, dependencies := analyzeGoMod(goModFile)
-			result.GoModFiles = append(result.GoModFiles, GoModResult{
-				Path:         relPath,
-				ModuleName:   moduleName,
-				Dependencies: dependencies,
-				ReplaceCount: replaceCount,
-			})
+			goMod, err := analyzeGoMod(goModFile)
+			if err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+			goMod.Path = relPath
+			result.GoModFiles = append(result.GoModFiles, goMod)
+			result.ReplaceCount += goMod.ReplaceCount
 		}
+		result.HasReplaceDirectives = result.ReplaceCount > 0
 	}
 
 	return result
 }
 
 // findGoModFiles recursively finds all go.mod files in the given directory.
-// Skips vendor directories and hidden directories.
-func findGoModFiles(rootPath string) []string {
-	var goModFiles []string
+// Skips vendor directories and hidden directories. Paths that cannot be read
+// are skipped and reported in the returned error.
+func findGoModFiles(rootPath string) ([]string, error) {
+	var (
+		goModFiles []string
+		walkErrs   []error
+	)
 
 	_ = filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
+			walkErrs = append(walkErrs, err)
 			return nil // Skip directories we can't read
 		}
 
@@ -277,272 +457,493 @@ func findGoModFiles(rootPath string) []string {
 		return nil
 	})
 
-	return goModFiles
+	return goModFiles, errors.Join(walkErrs...)
 }
 
-func analyzeGoMod(goModPath string) (moduleName string, replaceCount int, dependencies []string) {
-	file, err := os.Open(goModPath)
-	if err != nil {
-		return "", 0, nil
+// expectedModulePath returns the module path implied by the repo's location,
+// keeping the module's major version suffix (semantic import versioning):
+// github.com/foo/bar/v2 is expected in a directory named bar, and
+// gopkg.in/yaml.v3 in a directory named yaml or yaml.v3.
+//
+// In a GOPATH-style layout (.../src/github.com/foo/bar), the full path below
+// src is the expected import path. Otherwise only the last element of the
+// module path is compared with the directory name and replaced if it differs.
+func expectedModulePath(moduleName, repoPath string) string {
+	prefix, pathMajor, ok := module.SplitPathVersion(moduleName)
+	if !ok {
+		prefix, pathMajor = moduleName, ""
 	}
-	defer func() {
-		_ = file.Close()
-	}()
 
-	s := bufio.NewScanner(file)
-	inReplaceBlock := false
-	inRequireBlock := false
+	if importPath := gopathImportPath(repoPath); importPath != "" {
+		if pathMajor != "" && !strings.HasSuffix(importPath, pathMajor) {
+			return importPath + pathMajor
+		}
+		return importPath
+	}
 
-	for s.Scan() {
-		line := strings.TrimSpace(s.Text())
+	dirName := filepath.Base(repoPath)
+	parent, last := path.Split(prefix)
+	if dirName == last || dirName == last+pathMajor {
+		return moduleName
+	}
+	return parent + strings.TrimSuffix(dirName, pathMajor) + pathMajor
+}
 
-		// Get module name
-		if mod, found := strings.CutPrefix(line, "module "); found {
-			moduleName = strings.TrimSpace(mod)
+// gopathImportPath returns the import path of a directory in a GOPATH-style
+// layout: the path below the last "src" element that is followed by a host
+// name (containing a dot), e.g. github.com/foo/bar for ~/go/src/github.com/foo/bar.
+// Returns "" for other layouts.
+func gopathImportPath(dir string) string {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(dir)), "/")
+	for i := len(parts) - 3; i >= 0; i-- {
+		if parts[i] == "src" && strings.Contains(parts[i+1], ".") {
+			return strings.Join(parts[i+1:], "/")
 		}
+	}
+	return ""
+}
 
-		// Count replace directives
-		if strings.HasPrefix(line, "replace ") && !strings.HasPrefix(line, "replace (") {
-			replaceCount++
-		}
+// ModuleNode is a Go module in the dependency graph together with the repo
+// that owns it. Nested modules (e.g., repo/v2 or repo/tools) are separate
+// nodes owned by the same repo.
+type ModuleNode struct {
+	Path         string   // Module path
+	Repo         string   // Name of the repo containing the module
+	GoModPath    string   // Path to go.mod relative to the repo root
+	Dependencies []string // Required module paths
+}
 
-		// Handle replace block
-		if strings.HasPrefix(line, "replace (") {
-			inReplaceBlock = true
-			continue
-		}
-		if inReplaceBlock {
-			if line == ")" {
-				inReplaceBlock = false
+// ModuleNodes returns a node for every module declared by the results' go.mod
+// files, keyed by module path. Nested modules are included when the repos were
+// scanned with Recurse. If several repos declare the same module path, the
+// first one wins.
+func ModuleNodes(results []RepoResult) map[string]ModuleNode {
+	modules := make(map[string]ModuleNode)
+	for _, r := range results {
+		for _, gm := range r.GoMods() {
+			if gm.ModuleName == "" {
 				continue
 			}
-			if line != "" && !strings.HasPrefix(line, "//") {
-				replaceCount++
+			if _, seen := modules[gm.ModuleName]; seen {
+				continue
 			}
-		}
-
-		// Parse single-line require
-		if strings.HasPrefix(line, "require ") && !strings.HasPrefix(line, "require (") {
-			if dep := parseRequireLine(strings.TrimPrefix(line, "require ")); dep != "" {
-				dependencies = append(dependencies, dep)
+			modules[gm.ModuleName] = ModuleNode{
+				Path:         gm.ModuleName,
+				Repo:         r.Name,
+				GoModPath:    gm.Path,
+				Dependencies: gm.Dependencies,
 			}
 		}
+	}
+	return modules
+}
 
-		// Handle require block
-		if strings.HasPrefix(line, "require (") {
-			inRequireBlock = true
-			continue
-		}
-		if inRequireBlock {
-			if line == ")" {
-				inRequireBlock = false
+// internalDependencies returns the requirements of the repo's modules on
+// modules owned by other repos, one per dependency repo (the first requirement
+// found), sorted by dependency repo name. Requirements between modules of the
+// same repo are ignored.
+func internalDependencies(r RepoResult, modules map[string]ModuleNode) []Dependency {
+	var deps []Dependency
+	for _, gm := range r.GoMods() {
+		for _, req := range gm.Requires {
+			node, ok := modules[req.Path]
+			if !ok || node.Repo == r.Name {
 				continue
 			}
-			if dep := parseRequireLine(line); dep != "" {
-				dependencies = append(dependencies, dep)
+			if slices.ContainsFunc(deps, func(d Dependency) bool { return d.To == node.Repo }) {
+				continue
 			}
+			deps = append(deps, Dependency{
+				From:      r.Name,
+				To:        node.Repo,
+				GoModPath: gm.Path,
+				Module:    req.Path,
+				Version:   req.Version,
+				Line:      req.Line,
+			})
 		}
 	}
-
-	return moduleName, replaceCount, dependencies
-}
-
-// parseRequireLine extracts the module path from a require line.
-// Input: "github.com/foo/bar v1.2.3" or "github.com/foo/bar v1.2.3 // indirect"
-// Output: "github.com/foo/bar"
-func parseRequireLine(line string) string {
-	line = strings.TrimSpace(line)
-	if line == "" || strings.HasPrefix(line, "//") {
-		return ""
-	}
-	// Split on whitespace, first part is module path
-	parts := strings.Fields(line)
-	if len(parts) >= 1 {
-		return parts[0]
-	}
-	return ""
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].To < deps[j].To
+	})
+	return deps
 }
 
-// moduleMatchesPath checks if the module name ends with the directory name.
-// For example: github.com/grokify/gitscan should match directory "gitscan"
-func moduleMatchesPath(moduleName, dirName string) bool {
-	// Get the last segment of the module path
-	parts := strings.Split(moduleName, "/")
-	if len(parts) == 0 {
-		return false
+// internalDeps returns the names of the repos returned by internalDependencies.
+func internalDeps(r RepoResult, modules map[string]ModuleNode) []string {
+	var names []string
+	for _, d := range internalDependencies(r, modules) {
+		names = append(names, d.To)
 	}
-	lastPart := parts[len(parts)-1]
-	return lastPart == dirName
+	return names
 }
 
-// GetInternalDeps returns dependencies that are also in the results set (managed modules).
-// It maps from directory name to module name for matching.
+// GetInternalDeps returns the names of repos in the results set (managed
+// repos) that the repo depends on through any of its go.mod files.
 func GetInternalDeps(result RepoResult, allResults []RepoResult) []string {
-	// Build map of module names to directory names
-	moduleToDir := make(map[string]string)
-	for _, r := range allResults {
-		if r.ModuleName != "" {
-			moduleToDir[r.ModuleName] = r.Name
-		}
-	}
-
-	// Find which dependencies are internal
-	var internal []string
-	for _, dep := range result.Dependencies {
-		if dirName, ok := moduleToDir[dep]; ok {
-			internal = append(internal, dirName)
-		}
-	}
-	return internal
+	return internalDeps(result, ModuleNodes(allResults))
 }
 
 // GetTransitiveDependents returns all repos that transitively depend on the given seed repos.
 // This finds repos that may need updating when seed repos are updated.
+// A repo depends on another if any of its modules requires any module of the other.
 func GetTransitiveDependents(seeds []RepoResult, allResults []RepoResult) []RepoResult {
-	// Build module name to result map
-	moduleToResult := make(map[string]*RepoResult)
-	for i := range allResults {
-		if allResults[i].ModuleName != "" {
-			moduleToResult[allResults[i].ModuleName] = &allResults[i]
+	var seedNames []string
+	visited := make(map[string]bool)
+	for _, s := range seeds {
+		if len(s.GoMods()) > 0 && !visited[s.Name] {
+			visited[s.Name] = true
+			seedNames = append(seedNames, s.Name)
 		}
 	}
 
-	// Build reverse dependency graph: module -> modules that depend on it
-	dependents := make(map[string][]string)
+	walkDependents(seedNames, reverseDeps(allResults), func(repo, _ string) {
+		visited[repo] = true
+	})
+
+	// Collect results for all visited repos
+	var result []RepoResult
 	for _, r := range allResults {
-		if r.ModuleName == "" {
-			continue
-		}
-		for _, dep := range r.Dependencies {
-			if _, isManaged := moduleToResult[dep]; isManaged {
-				dependents[dep] = append(dependents[dep], r.ModuleName)
-			}
+		if visited[r.Name] {
+			result = append(result, r)
 		}
 	}
 
-	// BFS to find all transitive dependents
-	seedModules := make(map[string]bool)
-	for _, s := range seeds {
-		if s.ModuleName != "" {
-			seedModules[s.ModuleName] = true
+	return result
+}
+
+// reverseDeps returns the reverse dependency graph: repo -> names of the
+// repos that depend on it, sorted.
+func reverseDeps(allResults []RepoResult) map[string][]string {
+	modules := ModuleNodes(allResults)
+	dependents := make(map[string][]string)
+	for _, r := range allResults {
+		for _, dep := range internalDeps(r, modules) {
+			dependents[dep] = append(dependents[dep], r.Name)
 		}
 	}
+	for _, ds := range dependents {
+		slices.Sort(ds)
+	}
+	return dependents
+}
 
+// walkDependents visits the repos that transitively depend on the seeds
+// breadth-first, calling visit once for each repo reached (seeds excluded)
+// with the repo it was first reached from.
+func walkDependents(seeds []string, dependents map[string][]string, visit func(repo, from string)) {
 	visited := make(map[string]bool)
-	queue := make([]string, 0, len(seedModules))
-	for mod := range seedModules {
-		queue = append(queue, mod)
-		visited[mod] = true
+	queue := make([]string, 0, len(seeds))
+	for _, s := range seeds {
+		if !visited[s] {
+			visited[s] = true
+			queue = append(queue, s)
+		}
 	}
 
 	for len(queue) > 0 {
-		mod := queue[0]
+		repo := queue[0]
 		queue = queue[1:]
 
-		for _, dependent := range dependents[mod] {
+		for _, dependent := range dependents[repo] {
 			if !visited[dependent] {
 				visited[dependent] = true
+				visit(dependent, repo)
 				queue = append(queue, dependent)
 			}
 		}
 	}
+}
 
-	// Collect results for all visited modules
-	var result []RepoResult
-	for _, r := range allResults {
-		if r.ModuleName != "" && visited[r.ModuleName] {
-			result = append(result, r)
-		}
+// TopologicalSort returns repos in dependency order (dependencies before dependents).
+// Every module of a repo, including nested modules, contributes its
+// requirements to the repo's dependencies. Repos without a go.mod are omitted.
+// Repos in a cycle are listed together in name order, and repos depending on
+// them still come after them. Returns sorted results and the cycles detected.
+func TopologicalSort(results []RepoResult) ([]RepoResult, []Cycle) {
+	names, byName, graph := repoGraph(results)
+
+	order, components := orderNodes(names, graphDeps(graph))
+
+	sorted := make([]RepoResult, 0, len(order))
+	for _, name := range order {
+		sorted = append(sorted, byName[name])
 	}
 
-	return result
+	return sorted, findCycles(components, graph)
 }
 
-// TopologicalSort returns repos in dependency order (dependencies before dependents).
-// Uses Kahn's algorithm. Returns sorted results and any cycles detected.
-func TopologicalSort(results []RepoResult) ([]RepoResult, []string) {
-	// Build map of module names to results
-	moduleToResult := make(map[string]*RepoResult)
-	dirToResult := make(map[string]*RepoResult)
-	for i := range results {
-		if results[i].ModuleName != "" {
-			moduleToResult[results[i].ModuleName] = &results[i]
+// TopologicalLevels groups repos into levels (release waves) where each repo
+// only depends on repos in earlier levels, so the repos of a level can be
+// updated and released concurrently. Repos in a cycle share a level. The
+// number of levels is the length of the critical path. Repos within a level
+// are sorted by name. Returns the levels and the cycles detected.
+func TopologicalLevels(results []RepoResult) ([][]RepoResult, []Cycle) {
+	names, byName, graph := repoGraph(results)
+
+	nameLevels, components := levelNodes(names, graphDeps(graph))
+
+	levels := make([][]RepoResult, 0, len(nameLevels))
+	for _, level := range nameLevels {
+		repos := make([]RepoResult, 0, len(level))
+		for _, name := range level {
+			repos = append(repos, byName[name])
 		}
-		dirToResult[results[i].Name] = &results[i]
+		levels = append(levels, repos)
 	}
 
-	// Build adjacency list and in-degree count
-	// Edge A -> B means A depends on B (B must be updated before A)
-	inDegree := make(map[string]int)
-	dependents := make(map[string][]string) // module -> modules that depend on it
+	return levels, findCycles(components, graph)
+}
+
+// repoGraph returns the names of repos with a go.mod, the repos by name, and
+// the dependency graph between them: graph[A][B] is the requirement through
+// which repo A depends on repo B.
+func repoGraph(results []RepoResult) ([]string, map[string]RepoResult, map[string]map[string]Dependency) {
+	modules := ModuleNodes(results)
 
+	// Edge A -> B means A depends on B (B must be updated before A)
+	byName := make(map[string]RepoResult)
+	graph := make(map[string]map[string]Dependency)
+	var names []string
 	for _, r := range results {
-		if r.ModuleName == "" {
+		if len(r.GoMods()) == 0 {
+			continue
+		}
+		if _, seen := byName[r.Name]; !seen {
+			names = append(names, r.Name)
+		}
+		byName[r.Name] = r
+		graph[r.Name] = make(map[string]Dependency)
+		for _, d := range internalDependencies(r, modules) {
+			graph[r.Name][d.To] = d
+		}
+	}
+	return names, byName, graph
+}
+
+// SortedGoMods returns the repo's go.mod analyses ordered so that modules
+// come after the modules of the same repo they require (e.g., repo before
+// repo/tools). Modules in a cycle are listed together in path order, followed by
+// go.mod files without a module directive.
+func (r RepoResult) SortedGoMods() []GoModResult {
+	goMods := r.GoMods()
+	byModule := make(map[string]GoModResult)
+	deps := make(map[string][]string)
+	var modules []string
+	for _, gm := range goMods {
+		if gm.ModuleName == "" {
 			continue
 		}
-		inDegree[r.ModuleName] = 0
+		byModule[gm.ModuleName] = gm
+		modules = append(modules, gm.ModuleName)
+	}
+	for _, gm := range goMods {
+		for _, dep := range gm.Dependencies {
+			if _, ok := byModule[dep]; ok && gm.ModuleName != "" {
+				deps[gm.ModuleName] = append(deps[gm.ModuleName], dep)
+			}
+		}
+	}
+
+	order, _ := orderNodes(modules, deps)
+	sorted := make([]GoModResult, 0, len(goMods))
+	for _, mod := range order {
+		sorted = append(sorted, byModule[mod])
+	}
+	for _, gm := range goMods {
+		if gm.ModuleName == "" {
+			sorted = append(sorted, gm) // Unparseable go.mod files keep their position at the end
+		}
+	}
+	return sorted
+}
+
+// ReleaseUnit is a set of repos that are updated and released together,
+// such as the repos of a go.work workspace. Single repos form their own unit.
+type ReleaseUnit struct {
+	Name  string       // Repo name, or the member names joined with "+" for workspaces
+	Repos []RepoResult // Member repos in dependency order
+}
+
+// TopologicalSortUnits returns release units in dependency order, where each
+// group (e.g., from WorkspaceGroups) is contracted into a single unit.
+// Dependencies between members of the same unit are ignored. Repos without a
+// go.mod are omitted, as in TopologicalSort. Returns sorted units and the
+// cycles between units.
+func TopologicalSortUnits(results []RepoResult, groups [][]string) ([]ReleaseUnit, []Cycle) {
+	names, units, graph := unitGraph(results, groups)
+
+	order, components := orderNodes(names, graphDeps(graph))
+
+	sorted := make([]ReleaseUnit, 0, len(order))
+	for _, name := range order {
+		sorted = append(sorted, *units[name])
 	}
+	return sorted, findCycles(components, graph)
+}
 
+// TopologicalLevelsUnits groups release units into levels as TopologicalLevels
+// does for repos, where each group (e.g., from WorkspaceGroups) is contracted
+// into a single unit. Returns the levels and the cycles between units.
+func TopologicalLevelsUnits(results []RepoResult, groups [][]string) ([][]ReleaseUnit, []Cycle) {
+	names, units, graph := unitGraph(results, groups)
+
+	nameLevels, components := levelNodes(names, graphDeps(graph))
+
+	levels := make([][]ReleaseUnit, 0, len(nameLevels))
+	for _, level := range nameLevels {
+		unitLevel := make([]ReleaseUnit, 0, len(level))
+		for _, name := range level {
+			unitLevel = append(unitLevel, *units[name])
+		}
+		levels = append(levels, unitLevel)
+	}
+	return levels, findCycles(components, graph)
+}
+
+// unitGraph returns the names of release units, the units by name, and the
+// dependency graph between them. Each group is contracted into a single unit
+// and every other repo with a go.mod forms its own unit. Units are named after
+// their members in results, so group members that were filtered out do not
+// appear in unit names. Unit members are sorted in dependency order.
+func unitGraph(results []RepoResult, groups [][]string) ([]string, map[string]*ReleaseUnit, map[string]map[string]Dependency) {
+	// Map each repo to its unit name, made of the group members in results
+	inResults := make(map[string]bool)
+	for _, r := range results {
+		if len(r.GoMods()) > 0 {
+			inResults[r.Name] = true
+		}
+	}
+	unitOf := make(map[string]string)
+	for _, group := range groups {
+		var present []string
+		for _, repo := range group {
+			if inResults[repo] {
+				present = append(present, repo)
+			}
+		}
+		name := strings.Join(present, "+")
+		for _, repo := range present {
+			unitOf[repo] = name
+		}
+	}
+
+	units := make(map[string]*ReleaseUnit)
+	var names []string
 	for _, r := range results {
-		if r.ModuleName == "" {
+		if len(r.GoMods()) == 0 {
 			continue
 		}
-		for _, dep := range r.Dependencies {
-			if _, isManaged := moduleToResult[dep]; isManaged {
-				inDegree[r.ModuleName]++
-				dependents[dep] = append(dependents[dep], r.ModuleName)
+		name, ok := unitOf[r.Name]
+		if !ok {
+			name = r.Name
+		}
+		unit, ok := units[name]
+		if !ok {
+			unit = &ReleaseUnit{Name: name}
+			units[name] = unit
+			names = append(names, name)
+		}
+		unit.Repos = append(unit.Repos, r)
+	}
+
+	// Unit-level edges from repo-level internal dependencies
+	modules := ModuleNodes(results)
+	graph := make(map[string]map[string]Dependency)
+	for _, name := range names {
+		unit := units[name]
+		unit.Repos = sortUnitMembers(unit.Repos)
+		graph[name] = make(map[string]Dependency)
+		for _, r := range unit.Repos {
+			for _, d := range internalDependencies(r, modules) {
+				depUnit, ok := unitOf[d.To]
+				if !ok {
+					depUnit = d.To
+				}
+				if _, seen := graph[name][depUnit]; depUnit != name && !seen {
+					graph[name][depUnit] = d
+				}
+			}
+		}
+	}
+	return names, units, graph
+}
+
+// sortUnitMembers orders the members of a release unit by their dependencies
+// on each other. Members in a cycle are listed together in name order.
+func sortUnitMembers(members []RepoResult) []RepoResult {
+	sorted, _ := TopologicalSort(members)
+	return sorted
+}
+
+// kahnSort orders nodes so each node comes after the nodes it depends on,
+// using Kahn's algorithm. deps maps a node to its dependencies; dependencies
+// that are not in nodes are ignored. Ties are broken alphabetically for
+// deterministic output. Nodes that cannot be ordered because they are in or
+// downstream of a cycle are returned in remaining, sorted.
+func kahnSort(nodes []string, deps map[string][]string) (sorted, remaining []string) {
+	inDegree := make(map[string]int)
+	for _, n := range nodes {
+		inDegree[n] = 0
+	}
+
+	dependents := make(map[string][]string) // node -> nodes that depend on it
+	for _, n := range nodes {
+		for _, dep := range deps[n] {
+			if _, ok := inDegree[dep]; ok {
+				inDegree[n]++
+				dependents[dep] = append(dependents[dep], n)
 			}
 		}
 	}
 
-	// Kahn's algorithm
 	var queue []string
-	for mod, deg := range inDegree {
+	for n, deg := range inDegree {
 		if deg == 0 {
-			queue = append(queue, mod)
+			queue = append(queue, n)
 		}
 	}
 	// Sort for deterministic output
 	slices.Sort(queue)
 
-	var sorted []RepoResult
 	for len(queue) > 0 {
-		mod := queue[0]
+		n := queue[0]
 		queue = queue[1:]
-
-		if r, ok := moduleToResult[mod]; ok {
-			sorted = append(sorted, *r)
-		}
+		sorted = append(sorted, n)
 
 		// Decrease in-degree for dependents
-		deps := dependents[mod]
-		slices.Sort(deps)
-		for _, dep := range deps {
-			inDegree[dep]--
-			if inDegree[dep] == 0 {
-				queue = append(queue, dep)
+		ds := dependents[n]
+		slices.Sort(ds)
+		for _, d := range ds {
+			inDegree[d]--
+			if inDegree[d] == 0 {
+				queue = append(queue, d)
 			}
 		}
 	}
 
-	// Check for cycles
-	var cycles []string
-	for mod, deg := range inDegree {
+	for n, deg := range inDegree {
 		if deg > 0 {
-			cycles = append(cycles, mod)
+			remaining = append(remaining, n)
 		}
 	}
+	slices.Sort(remaining)
 
-	return sorted, cycles
+	return sorted, remaining
 }
 
 // getLatestModTime walks the directory tree and returns the most recent modification time.
-// Skips .git, vendor, and node_modules directories for performance.
-func getLatestModTime(rootPath string) time.Time {
-	var latest time.Time
+// Skips .git, vendor, and node_modules directories for performance. Paths that
+// cannot be read are skipped and reported in the returned error.
+func getLatestModTime(rootPath string) (time.Time, error) {
+	var (
+		latest   time.Time
+		walkErrs []error
+	)
 
 	_ = filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
+			walkErrs = append(walkErrs, err)
 			return nil // Skip inaccessible paths
 		}
 
@@ -558,6 +959,7 @@ func getLatestModTime(rootPath string) time.Time {
 		// Get file info for modification time
 		info, err := d.Info()
 		if err != nil {
+			walkErrs = append(walkErrs, err)
 			return nil
 		}
 
@@ -568,5 +970,5 @@ func getLatestModTime(rootPath string) time.Time {
 		return nil
 	})
 
-	return latest
+	return latest, errors.Join(walkErrs...)
 }
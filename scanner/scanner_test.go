@@ -0,0 +1,58 @@
+package scanner
+
+import "testing"
+
+func TestExpectedModulePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		moduleName string
+		repoPath   string
+		want       string
+	}{
+		{"matching directory", "github.com/foo/bar", "/work/bar", "github.com/foo/bar"},
+		{"renamed directory", "github.com/foo/bar", "/work/baz", "github.com/foo/baz"},
+		{"major version in bar", "github.com/foo/bar/v2", "/work/bar", "github.com/foo/bar/v2"},
+		{"major version in renamed directory", "github.com/foo/bar/v2", "/work/baz", "github.com/foo/baz/v2"},
+		{"gopkg.in in yaml", "gopkg.in/yaml.v3", "/work/yaml", "gopkg.in/yaml.v3"},
+		{"gopkg.in in yaml.v3", "gopkg.in/yaml.v3", "/work/yaml.v3", "gopkg.in/yaml.v3"},
+		{"gopkg.in in renamed directory", "gopkg.in/yaml.v3", "/work/yml", "gopkg.in/yml.v3"},
+		{"gopkg.in in renamed directory with suffix", "gopkg.in/yaml.v3", "/work/yml.v3", "gopkg.in/yml.v3"},
+		{"GOPATH", "github.com/foo/bar", "/home/u/go/src/github.com/foo/bar", "github.com/foo/bar"},
+		{"GOPATH with other owner", "github.com/foo/bar", "/home/u/go/src/github.com/baz/bar", "github.com/baz/bar"},
+		{"GOPATH with major version", "github.com/foo/bar/v2", "/home/u/go/src/github.com/foo/bar", "github.com/foo/bar/v2"},
+		{"GOPATH with gopkg.in", "gopkg.in/yaml.v3", "/home/u/go/src/gopkg.in/yaml.v3", "gopkg.in/yaml.v3"},
+		{"src without host", "github.com/foo/bar", "/home/u/src/bar", "github.com/foo/bar"},
+		{"src without host renamed", "github.com/foo/bar", "/home/u/src/projects/baz", "github.com/foo/baz"},
+		{"single-element module", "bar", "/work/bar", "bar"},
+		{"single-element module renamed", "bar", "/work/baz", "baz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expectedModulePath(tt.moduleName, tt.repoPath); got != tt.want {
+				t.Errorf("expectedModulePath(%q, %q) = %q, want %q", tt.moduleName, tt.repoPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGopathImportPath(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"/home/u/go/src/github.com/foo/bar", "github.com/foo/bar"},
+		{"/home/u/go/src/github.com/foo/bar/", "github.com/foo/bar"},
+		{"/home/u/go/src/gopkg.in/yaml.v3", "gopkg.in/yaml.v3"},
+		{"/src/example.com/mod", "example.com/mod"},
+		{"/home/u/src/bar", ""},
+		{"/home/u/src/projects/bar", ""},
+		{"/home/u/go/src/github.com", ""},
+		{"/home/u/src/github.com/foo/src/bar", "github.com/foo/src/bar"},
+		{"/work/bar", ""},
+	}
+	for _, tt := range tests {
+		if got := gopathImportPath(tt.dir); got != tt.want {
+			t.Errorf("gopathImportPath(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
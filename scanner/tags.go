@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// LatestModuleVersion returns the highest semver version tagged for the module
+// at modulePath, or "" if there is none. As with "go get @latest", release
+// versions are preferred: pre-release tags are only considered when no release
+// is tagged. Following the Go module tagging
+// convention, modules in subdirectories use tags prefixed with their directory
+// (e.g., "tools/v1.2.3" for dir "tools"). Only versions compatible with the
+// module path's major version suffix are considered, so "github.com/foo/bar/v2"
+// only matches v2.x.y tags.
+func LatestModuleVersion(tags []string, dir, modulePath string) string {
+	prefix := ""
+	if dir != "" && dir != "." {
+		prefix = path.Clean(dir) + "/"
+	}
+
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+
+	latestRelease, latestPrerelease := "", ""
+	for _, tag := range tags {
+		version, ok := strings.CutPrefix(tag, prefix)
+		if !ok || !semver.IsValid(version) || semver.Build(version) != "" {
+			continue
+		}
+		if module.CheckPathMajor(version, pathMajor) != nil {
+			continue
+		}
+		latest := &latestRelease
+		if semver.Prerelease(version) != "" {
+			latest = &latestPrerelease
+		}
+		if *latest == "" || semver.Compare(version, *latest) > 0 {
+			*latest = version
+		}
+	}
+	if latestRelease != "" {
+		return latestRelease
+	}
+	return latestPrerelease
+}
+
+// StalePin is a requirement on a managed module that is older than the
+// latest version tagged in the module's local repository.
+type StalePin struct {
+	Repo      string // Dependent repository name
+	GoModPath string // go.mod containing the requirement, relative to Repo
+	Module    string // Required module path
+	DepRepo   string // Repository providing the module
+	Required  string // Version required by the dependent
+	Tagged    string // Latest version tagged locally in DepRepo
+}
+
+// String returns a description such as "mogo v0.70.0 required, v0.74.0 tagged locally".
+func (p StalePin) String() string {
+	return fmt.Sprintf("%s %s required, %s tagged locally", p.DepRepo, p.Required, p.Tagged)
+}
+
+// TaggedModule is the latest version tagged for a module in its local repository.
+type TaggedModule struct {
+	Repo    string // Repository providing the module
+	Version string // Latest tagged version (see LatestModuleVersion)
+}
+
+// LatestTaggedModules returns the latest tagged version of every module
+// (root and nested) in the results, by module path. Tags must have been
+// collected during the scan (ScanOptions.CheckTags).
+func LatestTaggedModules(results []RepoResult) map[string]TaggedModule {
+	tagged := make(map[string]TaggedModule)
+	for _, r := range results {
+		for _, gm := range r.GoMods() {
+			if gm.ModuleName == "" {
+				continue
+			}
+			if v := LatestModuleVersion(r.Tags, path.Dir(gm.Path), gm.ModuleName); v != "" {
+				tagged[gm.ModuleName] = TaggedModule{Repo: r.Name, Version: v}
+			}
+		}
+	}
+	return tagged
+}
+
+// GetStalePins returns requirements in result's go.mod files (root and nested)
+// on managed modules whose required version is older than the latest version
+// tagged in the providing repo, from LatestTaggedModules.
+func GetStalePins(result RepoResult, tagged map[string]TaggedModule) []StalePin {
+	var stale []StalePin
+	for _, gm := range result.GoMods() {
+		for _, req := range gm.Requires {
+			tm, ok := tagged[req.Path]
+			if !ok || tm.Repo == result.Name {
+				continue
+			}
+			if semver.Compare(req.Version, tm.Version) < 0 {
+				stale = append(stale, StalePin{
+					Repo:      result.Name,
+					GoModPath: gm.Path,
+					Module:    req.Path,
+					DepRepo:   tm.Repo,
+					Required:  req.Version,
+					Tagged:    tm.Version,
+				})
+			}
+		}
+	}
+	return stale
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLatestModuleVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       []string
+		dir        string
+		modulePath string
+		want       string
+	}{
+		{"no tags", nil, "", "example.com/mod", ""},
+		{"highest release", []string{"v1.2.0", "v1.10.0", "v1.9.1", "not-semver"}, "", "example.com/mod", "v1.10.0"},
+		{"release preferred over newer pre-release", []string{"v1.2.0", "v1.3.0-rc.1"}, "", "example.com/mod", "v1.2.0"},
+		{"pre-release without release", []string{"v1.3.0-rc.1", "v1.3.0-rc.2", "v1.3.0-beta.1"}, "", "example.com/mod", "v1.3.0-rc.2"},
+		{"build metadata ignored", []string{"v1.2.0", "v1.3.0+meta"}, "", "example.com/mod", "v1.2.0"},
+		{"major version suffix", []string{"v1.5.0", "v2.1.0", "v3.0.0"}, "", "example.com/mod/v2", "v2.1.0"},
+		{"v0/v1 without suffix", []string{"v0.9.0", "v1.1.0", "v2.0.0"}, "", "example.com/mod", "v1.1.0"},
+		{"incompatible v2+ without go.mod", []string{"v1.1.0", "v2.0.0+incompatible"}, "", "example.com/mod", "v1.1.0"},
+		{"nested module prefix", []string{"v1.4.0", "tools/v0.2.0", "tools/v0.3.0-rc.1"}, "tools", "example.com/mod/tools", "v0.2.0"},
+		{"nested module without its tags", []string{"v1.4.0"}, "tools", "example.com/mod/tools", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LatestModuleVersion(tt.tags, tt.dir, tt.modulePath); got != tt.want {
+				t.Errorf("LatestModuleVersion(%v, %q, %q) = %q, want %q", tt.tags, tt.dir, tt.modulePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStalePins(t *testing.T) {
+	lib := RepoResult{
+		Name:       "lib",
+		ModuleName: "example.com/lib",
+		GoMod:      &GoModResult{Path: "go.mod", ModuleName: "example.com/lib"},
+		GoModFiles: []GoModResult{{Path: "tools/go.mod", ModuleName: "example.com/lib/tools"}},
+		Tags:       []string{"v1.1.0", "v1.2.0", "v1.3.0-rc.1", "tools/v0.5.0"},
+	}
+	app := RepoResult{
+		Name: "app",
+		GoMod: &GoModResult{Path: "go.mod", ModuleName: "example.com/app", Requires: []Require{
+			{Path: "example.com/lib", Version: "v1.1.0"},
+			{Path: "example.com/lib/tools", Version: "v0.5.0"},
+			{Path: "example.com/other", Version: "v0.1.0"},
+		}},
+		GoModFiles: []GoModResult{{Path: "cmd/go.mod", ModuleName: "example.com/app/cmd", Requires: []Require{
+			{Path: "example.com/lib", Version: "v1.2.0"},
+			{Path: "example.com/lib/tools", Version: "v0.4.0"},
+		}}},
+	}
+	tagged := LatestTaggedModules([]RepoResult{lib, app})
+
+	var got []string
+	for _, pin := range GetStalePins(app, tagged) {
+		got = append(got, pin.GoModPath+": "+pin.String())
+	}
+	want := []string{
+		"go.mod: lib v1.1.0 required, v1.2.0 tagged locally",
+		"cmd/go.mod: lib v0.4.0 required, v0.5.0 tagged locally",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("GetStalePins() = %q, want %q", got, want)
+	}
+	if pins := GetStalePins(lib, tagged); len(pins) != 0 {
+		t.Errorf("GetStalePins(lib) = %v, want none", pins)
+	}
+}
@@ -0,0 +1,135 @@
+package scanner
+
+import (
+	"path"
+	"slices"
+	"sort"
+
+	"golang.org/x/mod/semver"
+)
+
+// VersionSkew describes how far a required version lags behind the newest
+// version of the same module required elsewhere.
+type VersionSkew int
+
+const (
+	SkewNone  VersionSkew = iota // Same as the newest version
+	SkewPatch                    // Same major.minor, older patch (or pre-release/pseudo-version)
+	SkewMinor                    // Same major, older minor
+	SkewMajor                    // Older major
+)
+
+// String returns the lowercase name of the skew level.
+func (s VersionSkew) String() string {
+	switch s {
+	case SkewPatch:
+		return "patch"
+	case SkewMinor:
+		return "minor"
+	case SkewMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// CompareSkew returns how far version lags behind newest.
+func CompareSkew(version, newest string) VersionSkew {
+	switch {
+	case semver.Compare(version, newest) >= 0:
+		return SkewNone
+	case semver.Major(version) != semver.Major(newest):
+		return SkewMajor
+	case semver.MajorMinor(version) != semver.MajorMinor(newest):
+		return SkewMinor
+	default:
+		return SkewPatch
+	}
+}
+
+// ModuleUsage is a single go.mod that requires a module at a specific version.
+type ModuleUsage struct {
+	Repo      string // Repository directory name
+	GoModPath string // Path to go.mod relative to repo root
+	Version   string
+	Indirect  bool
+}
+
+// Label returns the repo name, with the nested module directory appended
+// for go.mod files below the repo root (e.g., "myrepo/tools").
+func (u ModuleUsage) Label() string {
+	dir := path.Dir(u.GoModPath)
+	if dir == "." || dir == "" {
+		return u.Repo
+	}
+	return u.Repo + "/" + dir
+}
+
+// ModuleVersions groups all usages of a required module across repos.
+type ModuleVersions struct {
+	Path   string        // Required module path
+	Newest string        // Newest version required by any repo
+	Usages []ModuleUsage // Usages sorted newest version first, then by label
+}
+
+// Versions returns the distinct required versions, newest first.
+func (m ModuleVersions) Versions() []string {
+	var versions []string
+	for _, u := range m.Usages {
+		if !slices.Contains(versions, u.Version) {
+			versions = append(versions, u.Version)
+		}
+	}
+	return versions
+}
+
+// HasSkew returns true if the module is required at more than one version.
+func (m ModuleVersions) HasSkew() bool {
+	return len(m.Versions()) > 1
+}
+
+// CollectModuleVersions gathers the versions of every required module across
+// all go.mod files in the results (root and nested). Indirect requirements are
+// only included when includeIndirect is true. Results are sorted by module path.
+func CollectModuleVersions(results []RepoResult, includeIndirect bool) []ModuleVersions {
+	byPath := make(map[string]*ModuleVersions)
+	for _, r := range results {
+		for _, gm := range r.GoMods() {
+			for _, req := range gm.Requires {
+				if req.Indirect && !includeIndirect {
+					continue
+				}
+				mv, ok := byPath[req.Path]
+				if !ok {
+					mv = &ModuleVersions{Path: req.Path}
+					byPath[req.Path] = mv
+				}
+				mv.Usages = append(mv.Usages, ModuleUsage{
+					Repo:      r.Name,
+					GoModPath: gm.Path,
+					Version:   req.Version,
+					Indirect:  req.Indirect,
+				})
+				if mv.Newest == "" || semver.Compare(req.Version, mv.Newest) > 0 {
+					mv.Newest = req.Version
+				}
+			}
+		}
+	}
+
+	modules := make([]ModuleVersions, 0, len(byPath))
+	for _, mv := range byPath {
+		sort.Slice(mv.Usages, func(i, j int) bool {
+			if c := semver.Compare(mv.Usages[i].Version, mv.Usages[j].Version); c != 0 {
+				return c > 0
+			}
+			return mv.Usages[i].Label() < mv.Usages[j].Label()
+		})
+		modules = append(modules, *mv)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+
+	return modules
+}
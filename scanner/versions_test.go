@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestCompareSkew(t *testing.T) {
+	tests := []struct {
+		version string
+		newest  string
+		want    VersionSkew
+	}{
+		{"v1.2.3", "v1.2.3", SkewNone},
+		{"v1.3.0", "v1.2.3", SkewNone},
+		{"v1.2.2", "v1.2.3", SkewPatch},
+		{"v1.2.3-rc.1", "v1.2.3", SkewPatch},
+		{"v1.2.3-0.20240101000000-abcdefabcdef", "v1.2.3", SkewPatch},
+		{"v1.1.9", "v1.2.0", SkewMinor},
+		{"v0.9.0", "v0.10.0", SkewMinor},
+		{"v1.9.0", "v2.0.0", SkewMajor},
+		{"v0.1.0", "v1.0.0", SkewMajor},
+		{"v2.0.0+incompatible", "v3.0.0+incompatible", SkewMajor},
+	}
+	for _, tt := range tests {
+		if got := CompareSkew(tt.version, tt.newest); got != tt.want {
+			t.Errorf("CompareSkew(%q, %q) = %s, want %s", tt.version, tt.newest, got, tt.want)
+		}
+	}
+}
+
+func TestCollectModuleVersions(t *testing.T) {
+	results := []RepoResult{
+		{
+			Name: "app",
+			GoMod: &GoModResult{Path: "go.mod", Requires: []Require{
+				{Path: "example.com/lib", Version: "v1.2.0"},
+				{Path: "example.com/util", Version: "v0.3.0", Indirect: true},
+			}},
+			GoModFiles: []GoModResult{{Path: "tools/go.mod", Requires: []Require{
+				{Path: "example.com/lib", Version: "v1.10.0"},
+			}}},
+		},
+		{
+			Name: "svc",
+			GoMod: &GoModResult{Path: "go.mod", Requires: []Require{
+				{Path: "example.com/lib", Version: "v1.2.0"},
+				{Path: "example.com/util", Version: "v0.4.0"},
+			}},
+		},
+		{Name: "docs"},
+	}
+
+	format := func(modules []ModuleVersions) []string {
+		var got []string
+		for _, mv := range modules {
+			s := fmt.Sprintf("%s newest=%s skew=%v:", mv.Path, mv.Newest, mv.HasSkew())
+			for _, u := range mv.Usages {
+				s += " " + u.Label() + "@" + u.Version
+			}
+			got = append(got, s)
+		}
+		return got
+	}
+
+	got := format(CollectModuleVersions(results, false))
+	want := []string{
+		"example.com/lib newest=v1.10.0 skew=true: app/tools@v1.10.0 app@v1.2.0 svc@v1.2.0",
+		"example.com/util newest=v0.4.0 skew=false: svc@v0.4.0",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("CollectModuleVersions(direct) =\n%q\nwant\n%q", got, want)
+	}
+
+	modules := CollectModuleVersions(results, true)
+	got = format(modules)
+	want[1] = "example.com/util newest=v0.4.0 skew=true: svc@v0.4.0 app@v0.3.0"
+	if !slices.Equal(got, want) {
+		t.Errorf("CollectModuleVersions(indirect) =\n%q\nwant\n%q", got, want)
+	}
+	if versions := modules[0].Versions(); !slices.Equal(versions, []string{"v1.10.0", "v1.2.0"}) {
+		t.Errorf("Versions() = %v, want [v1.10.0 v1.2.0]", versions)
+	}
+
+	if modules := CollectModuleVersions(nil, true); len(modules) != 0 {
+		t.Errorf("CollectModuleVersions(nil) = %v, want none", modules)
+	}
+}
@@ -0,0 +1,307 @@
+package scanner
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// OSVEntry is a vulnerability in the OSV format (https://ossf.github.io/osv-schema/).
+// Only the fields needed to match Go module versions are decoded.
+type OSVEntry struct {
+	ID        string        `json:"id"`
+	Aliases   []string      `json:"aliases,omitempty"`
+	Summary   string        `json:"summary,omitempty"`
+	Withdrawn string        `json:"withdrawn,omitempty"`
+	Affected  []OSVAffected `json:"affected"`
+}
+
+// OSVAffected is a package affected by an OSV entry.
+type OSVAffected struct {
+	Package  OSVPackage `json:"package"`
+	Ranges   []OSVRange `json:"ranges,omitempty"`
+	Versions []string   `json:"versions,omitempty"`
+}
+
+// OSVPackage identifies an affected package. For the Go ecosystem, Name is
+// the module path.
+type OSVPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// OSVRange is a range of affected versions, described by ordered events.
+type OSVRange struct {
+	Type   string     `json:"type"`
+	Events []OSVEvent `json:"events"`
+}
+
+// OSVEvent is a version where a range starts (Introduced) or ends (Fixed,
+// LastAffected). Versions are semver without the "v" prefix, and an
+// Introduced version of "0" means all versions.
+type OSVEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// VulnDB is a local vulnerability database, indexed by Go module path.
+type VulnDB struct {
+	Entries  int // Number of loaded entries affecting Go modules
+	byModule map[string][]*OSVEntry
+}
+
+// LoadVulnDB loads the OSV entries of a vulnerability database from a
+// directory (searched recursively for .json files) or a .zip archive, such as
+// the Go vulnerability database or an osv.dev ecosystem export. Only entries
+// affecting the Go ecosystem are kept; withdrawn entries and JSON files that
+// are not OSV entries (e.g., database indexes) are ignored.
+func LoadVulnDB(dbPath string) (*VulnDB, error) {
+	db := &VulnDB{byModule: make(map[string][]*OSVEntry)}
+
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		err = filepath.WalkDir(dbPath, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(p, ".json") {
+				return err
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			return db.add(p, data)
+		})
+		return db, err
+	}
+
+	zr, err := zip.OpenReader(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a directory or zip archive: %w", dbPath, err)
+	}
+	defer zr.Close()
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || !strings.HasSuffix(zf.Name, ".json") {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", zf.Name, err)
+		}
+		if err := db.add(zf.Name, data); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// add decodes an OSV entry and indexes it by the Go modules it affects.
+func (db *VulnDB) add(name string, data []byte) error {
+	var entry OSVEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil // Valid JSON, but not an OSV entry
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if entry.ID == "" || entry.Withdrawn != "" {
+		return nil
+	}
+
+	var modules []string
+	for _, a := range entry.Affected {
+		if a.Package.Ecosystem == "Go" && !slices.Contains(modules, a.Package.Name) {
+			modules = append(modules, a.Package.Name)
+		}
+	}
+	if len(modules) == 0 {
+		return nil
+	}
+	db.Entries++
+	for _, m := range modules {
+		db.byModule[m] = append(db.byModule[m], &entry)
+	}
+	return nil
+}
+
+// Vuln is a vulnerability affecting a required module version.
+type Vuln struct {
+	ID      string
+	Aliases []string // Other IDs, e.g. CVEs
+	Summary string
+	Fixed   string // First fixed version above the required version ("" if none)
+}
+
+// Lookup returns the vulnerabilities affecting version of modulePath, sorted
+// by ID.
+func (db *VulnDB) Lookup(modulePath, version string) []Vuln {
+	var vulns []Vuln
+	for _, entry := range db.byModule[modulePath] {
+		for _, a := range entry.Affected {
+			if a.Package.Ecosystem != "Go" || a.Package.Name != modulePath {
+				continue
+			}
+			if affected, fixed := a.contains(version); affected {
+				vulns = append(vulns, Vuln{
+					ID:      entry.ID,
+					Aliases: entry.Aliases,
+					Summary: entry.Summary,
+					Fixed:   fixed,
+				})
+				break
+			}
+		}
+	}
+	sort.Slice(vulns, func(i, j int) bool {
+		return vulns[i].ID < vulns[j].ID
+	})
+	return vulns
+}
+
+// contains reports whether version is affected, and the version fixing it.
+// An entry without ranges or versions affects all versions.
+func (a OSVAffected) contains(version string) (bool, string) {
+	if len(a.Ranges) == 0 && len(a.Versions) == 0 {
+		return true, ""
+	}
+	for _, v := range a.Versions {
+		if osvSemver(v) == version {
+			return true, ""
+		}
+	}
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+		if affected, fixed := r.contains(version); affected {
+			return true, fixed
+		}
+	}
+	return false, ""
+}
+
+// contains evaluates the range events in version order: an introduced event
+// at or below version starts an affected interval, and a fixed (or
+// last_affected) event ends it.
+func (r OSVRange) contains(version string) (bool, string) {
+	events := slices.Clone(r.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return semver.Compare(events[i].version(), events[j].version()) < 0
+	})
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case !affected && e.Introduced != "":
+			affected = e.Introduced == "0" || semver.Compare(version, osvSemver(e.Introduced)) >= 0
+		case affected && e.Fixed != "":
+			if semver.Compare(version, osvSemver(e.Fixed)) < 0 {
+				return true, osvSemver(e.Fixed)
+			}
+			affected = false
+		case affected && e.LastAffected != "":
+			if semver.Compare(version, osvSemver(e.LastAffected)) <= 0 {
+				return true, ""
+			}
+			affected = false
+		}
+	}
+	return affected, ""
+}
+
+// version returns the event's version for ordering; an introduced version of
+// "0" sorts first.
+func (e OSVEvent) version() string {
+	switch {
+	case e.Introduced == "0":
+		return "v0.0.0-0"
+	case e.Introduced != "":
+		return osvSemver(e.Introduced)
+	case e.Fixed != "":
+		return osvSemver(e.Fixed)
+	}
+	return osvSemver(e.LastAffected)
+}
+
+// osvSemver adds the "v" prefix used by Go module versions to an OSV version.
+func osvSemver(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
+
+// VulnerableRequire is a go.mod requirement affected by vulnerabilities.
+type VulnerableRequire struct {
+	ModuleUsage
+	Module      string // Required module path
+	Replacement string // Module version the requirement is replaced with, e.g. "example.com/fork v1.2.0" ("" if not replaced)
+	Vulns       []Vuln // Sorted by ID
+}
+
+// FindVulnerabilities matches the required module versions of every analyzed
+// go.mod (root and nested) against db. Requirements replaced with another
+// module version are checked at that version instead; requirements replaced
+// with a local directory are not checked. Indirect requirements are only
+// checked when includeIndirect is true. Results are sorted by label, then
+// module path.
+func FindVulnerabilities(results []RepoResult, db *VulnDB, includeIndirect bool) []VulnerableRequire {
+	var found []VulnerableRequire
+	for _, r := range results {
+		for _, gm := range r.GoMods() {
+			for _, req := range gm.Requires {
+				if req.Indirect && !includeIndirect {
+					continue
+				}
+				path, version, replacement := req.Path, req.Version, ""
+				if rep, ok := gm.Replacement(req.Path, req.Version); ok {
+					if rep.IsLocal() {
+						continue // Local code is not a published module version
+					}
+					path, version = rep.NewPath, rep.NewVersion
+					replacement = rep.NewPath + " " + rep.NewVersion
+				}
+				vulns := db.Lookup(path, version)
+				if len(vulns) == 0 {
+					continue
+				}
+				found = append(found, VulnerableRequire{
+					ModuleUsage: ModuleUsage{
+						Repo:      r.Name,
+						GoModPath: gm.Path,
+						Version:   req.Version,
+						Indirect:  req.Indirect,
+					},
+					Module:      req.Path,
+					Replacement: replacement,
+					Vulns:       vulns,
+				})
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Label() != found[j].Label() {
+			return found[i].Label() < found[j].Label()
+		}
+		return found[i].Module < found[j].Module
+	})
+	return found
+}
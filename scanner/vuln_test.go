@@ -0,0 +1,207 @@
+package scanner
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// osvFixtures are OSV files of a small vulnerability database.
+var osvFixtures = map[string]string{
+	// All versions before v1.2.0
+	"GO-2026-0001.json": `{
+		"id": "GO-2026-0001", "aliases": ["CVE-2026-0001"], "summary": "Panic in a",
+		"affected": [{"package": {"name": "example.com/a", "ecosystem": "Go"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.0"}]}]}]
+	}`,
+	// Two intervals, with events out of order
+	"GO-2026-0002.json": `{
+		"id": "GO-2026-0002", "summary": "Leak in a",
+		"affected": [{"package": {"name": "example.com/a", "ecosystem": "Go"},
+			"ranges": [{"type": "SEMVER", "events": [
+				{"introduced": "1.3.0"}, {"fixed": "1.3.2"}, {"introduced": "1.0.0"}, {"fixed": "1.0.5"}]}]}]
+	}`,
+	// No fix yet: last affected version
+	"nested/GO-2026-0003.json": `{
+		"id": "GO-2026-0003",
+		"affected": [{"package": {"name": "example.com/b", "ecosystem": "Go"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0.2.0"}, {"last_affected": "0.4.0"}]}]}]
+	}`,
+	// Explicit versions list
+	"GO-2026-0004.json": `{
+		"id": "GO-2026-0004",
+		"affected": [{"package": {"name": "example.com/c", "ecosystem": "Go"}, "versions": ["1.1.0", "v1.1.5"]}]
+	}`,
+	"GO-2026-0005.json": `{
+		"id": "GO-2026-0005", "withdrawn": "2026-02-01T00:00:00Z",
+		"affected": [{"package": {"name": "example.com/a", "ecosystem": "Go"}}]
+	}`,
+	"PYSEC-2026-1.json": `{
+		"id": "PYSEC-2026-1",
+		"affected": [{"package": {"name": "example.com/a", "ecosystem": "PyPI"}}]
+	}`,
+	"GO-2026-0006.json": `{
+		"id": "GO-2026-0006",
+		"affected": [{"package": {"name": "example.com/fork", "ecosystem": "Go"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "2.0.0"}]}]}]
+	}`,
+	"index/modules.json": `[{"path": "example.com/a"}]`,
+	"README.md":          "not JSON",
+}
+
+// vulnDBs writes osvFixtures as a directory and as a zip archive, and
+// returns both paths.
+func vulnDBs(t *testing.T) map[string]string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "vulndb")
+	for name, content := range osvFixtures {
+		writeFile(t, dir, name, content)
+	}
+
+	zipPath := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range osvFixtures {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return map[string]string{"directory": dir, "zip": zipPath}
+}
+
+func TestVulnDBLookup(t *testing.T) {
+	tests := []struct {
+		module  string
+		version string
+		want    []string // "ID fixed-version"
+	}{
+		{"example.com/a", "v0.0.1", []string{"GO-2026-0001 v1.2.0"}},
+		{"example.com/a", "v1.0.3", []string{"GO-2026-0001 v1.2.0", "GO-2026-0002 v1.0.5"}},
+		{"example.com/a", "v1.0.5", []string{"GO-2026-0001 v1.2.0"}},
+		{"example.com/a", "v1.2.0", nil},
+		{"example.com/a", "v1.3.0", []string{"GO-2026-0002 v1.3.2"}},
+		{"example.com/a", "v1.3.2-rc.1", []string{"GO-2026-0002 v1.3.2"}},
+		{"example.com/a", "v1.3.2", nil},
+		{"example.com/b", "v0.1.9", nil},
+		{"example.com/b", "v0.2.0", []string{"GO-2026-0003 "}},
+		{"example.com/b", "v0.4.0", []string{"GO-2026-0003 "}},
+		{"example.com/b", "v0.4.1", nil},
+		{"example.com/c", "v1.1.0", []string{"GO-2026-0004 "}},
+		{"example.com/c", "v1.1.5", []string{"GO-2026-0004 "}},
+		{"example.com/c", "v1.1.1", nil},
+		{"example.com/other", "v1.0.0", nil},
+	}
+	for kind, path := range vulnDBs(t) {
+		t.Run(kind, func(t *testing.T) {
+			db, err := LoadVulnDB(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if db.Entries != 5 {
+				t.Errorf("LoadVulnDB() loaded %d entries, want 5", db.Entries)
+			}
+			for _, tt := range tests {
+				var got []string
+				for _, v := range db.Lookup(tt.module, tt.version) {
+					got = append(got, v.ID+" "+v.Fixed)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("Lookup(%s, %s) = %q, want %q", tt.module, tt.version, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadVulnDBErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "GO-bad.json", `{"id": "GO-bad",`)
+	if _, err := LoadVulnDB(dir); err == nil || !strings.Contains(err.Error(), "GO-bad.json") {
+		t.Errorf("LoadVulnDB(malformed JSON) error = %v, want one naming the file", err)
+	}
+
+	writeFile(t, dir, "db.txt", "not a zip")
+	if _, err := LoadVulnDB(filepath.Join(dir, "db.txt")); err == nil || !strings.Contains(err.Error(), "not a directory or zip archive") {
+		t.Errorf("LoadVulnDB(text file) error = %v", err)
+	}
+	if _, err := LoadVulnDB(filepath.Join(dir, "missing")); err == nil {
+		t.Error("LoadVulnDB(missing path) returned no error")
+	}
+}
+
+func TestFindVulnerabilities(t *testing.T) {
+	db, err := LoadVulnDB(vulnDBs(t)["directory"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []RepoResult{
+		{
+			Name: "app",
+			GoMod: &GoModResult{Path: "go.mod", Requires: []Require{
+				{Path: "example.com/a", Version: "v1.1.0"},
+				{Path: "example.com/b", Version: "v0.3.0", Indirect: true},
+				{Path: "example.com/c", Version: "v1.1.0"},
+				{Path: "example.com/safe", Version: "v1.0.0"},
+			}, Replaces: []Replace{
+				// The local replace applies only to v1.1.0; the fork to all other versions
+				{OldPath: "example.com/c", OldVersion: "v1.1.0", NewPath: "../c", Kind: ReplaceLocalPath},
+				{OldPath: "example.com/c", NewPath: "example.com/c", NewVersion: "v1.1.5", Kind: ReplaceVersionPin},
+				{OldPath: "example.com/safe", NewPath: "example.com/fork", NewVersion: "v1.5.0", Kind: ReplaceFork},
+			}},
+			GoModFiles: []GoModResult{{Path: "tools/go.mod", Requires: []Require{
+				{Path: "example.com/c", Version: "v1.1.0"},
+			}}},
+		},
+		{
+			Name: "lib",
+			GoMod: &GoModResult{Path: "go.mod", Requires: []Require{
+				{Path: "example.com/a", Version: "v1.2.0"},
+			}},
+		},
+	}
+
+	format := func(found []VulnerableRequire) []string {
+		var got []string
+		for _, v := range found {
+			s := v.Label() + ": " + v.Module + " " + v.Version
+			if v.Replacement != "" {
+				s += " => " + v.Replacement
+			}
+			for _, vuln := range v.Vulns {
+				s += " " + vuln.ID
+			}
+			got = append(got, s)
+		}
+		return got
+	}
+
+	want := []string{
+		"app: example.com/a v1.1.0 GO-2026-0001",
+		"app: example.com/safe v1.0.0 => example.com/fork v1.5.0 GO-2026-0006",
+		"app/tools: example.com/c v1.1.0 GO-2026-0004",
+	}
+	if got := format(FindVulnerabilities(results, db, false)); !slices.Equal(got, want) {
+		t.Errorf("FindVulnerabilities(direct) =\n%q\nwant\n%q", got, want)
+	}
+
+	want = slices.Insert(want, 1, "app: example.com/b v0.3.0 GO-2026-0003")
+	if got := format(FindVulnerabilities(results, db, true)); !slices.Equal(got, want) {
+		t.Errorf("FindVulnerabilities(indirect) =\n%q\nwant\n%q", got, want)
+	}
+}